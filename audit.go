@@ -0,0 +1,74 @@
+package env
+
+import (
+	"io"
+	"sort"
+)
+
+// AuditReport describes the differences between an environment and a reference
+// manifest.  Only names are reported, as values may be secret.
+type AuditReport struct {
+	Missing []string // in the manifest, but unset in the environment
+	Extra   []string // variables of the set which are set in the environment, but not in the manifest
+	Changed []string // set in both, with different values
+}
+
+// OK reports whether the environment matches the manifest.
+func (r *AuditReport) OK() bool {
+	return len(r.Missing) == 0 && len(r.Extra) == 0 && len(r.Changed) == 0
+}
+
+// Audit compares the environment provided by the Getter with a reference manifest in
+// .env format (see Dotenv and VarSet.WriteDotenv), for detecting drift in long-lived
+// hosts.  Every variable in the manifest is checked, along with each variable in the
+// set.  Names in the report are sorted.
+//
+// The variables of the set are compared by their effective values, as Parse would
+// find them: from their fallbacks if they are unset, or else their defaults.  Secret
+// variables are only checked for presence, since WriteDotenv redacts their values.
+func Audit(vs *VarSet, g Getter, manifest io.Reader) (*AuditReport, error) {
+	want, err := Dotenv(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for name := range want {
+		names[name] = true
+	}
+	vars := make(map[string]*Var)
+	vs.Visit(func(x *Var) {
+		names[x.Name] = true
+		vars[x.Name] = x
+	})
+
+	r := new(AuditReport)
+	for name := range names {
+		wz, inManifest := want[name]
+		var z string
+		var set, ok, secret bool
+		if x := vars[name]; x != nil {
+			z, _, set = x.lookup(g, nil)
+			ok = set
+			if !ok && x.def != nil {
+				z, ok = x.def(), true
+			}
+			secret = x.secret
+		} else {
+			z, set = g.Get(name)
+			ok = set
+		}
+		switch {
+		case inManifest && !ok:
+			r.Missing = append(r.Missing, name)
+		case !inManifest && set:
+			r.Extra = append(r.Extra, name)
+		case inManifest && !secret && z != wz:
+			r.Changed = append(r.Changed, name)
+		}
+	}
+	sort.Strings(r.Missing)
+	sort.Strings(r.Extra)
+	sort.Strings(r.Changed)
+	return r, nil
+}
@@ -0,0 +1,80 @@
+package env_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestAudit(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	vs.Int("WORKERS", "audit test")
+	vs.String("NAME", "audit test")
+	vs.String("REGION", "audit test")
+	vs.String("DEBUG", "audit test")
+
+	manifest := "SVC_WORKERS=4\nSVC_NAME=\"a\"\nSVC_REGION=us\nOTHER=x\n"
+	live := testGetter{"SVC_WORKERS": "8", "SVC_NAME": "a", "SVC_DEBUG": "1", "OTHER": "x"}
+
+	r, err := env.Audit(vs, live, strings.NewReader(manifest))
+	if err != nil {
+		t.Fatalf("unexpected error from Audit: %v", err)
+	}
+	expected := &env.AuditReport{
+		Missing: []string{"SVC_REGION"},
+		Extra:   []string{"SVC_DEBUG"},
+		Changed: []string{"SVC_WORKERS"},
+	}
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("got %+v, expected %+v", r, expected)
+	}
+	if r.OK() {
+		t.Errorf("r.OK() = true, expected false")
+	}
+
+	r, err = env.Audit(vs, testGetter{"SVC_WORKERS": "4", "SVC_NAME": "a", "SVC_REGION": "us", "OTHER": "x"}, strings.NewReader(manifest))
+	if err != nil || !r.OK() {
+		t.Errorf("got (%+v, %v), expected no differences", r, err)
+	}
+
+	if _, err := env.Audit(vs, live, strings.NewReader("A\n")); err == nil {
+		t.Errorf("expected error from invalid manifest")
+	}
+}
+
+func TestAuditDotenv(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	vs.String("NAME", "audit test")
+	vs.String("LEVEL", "audit test", env.Default("info"))
+	vs.String("TOKEN", "audit test", env.Secret())
+	vs.DSN("DB", "audit test")
+	vs.String("REGION", "audit test", env.FallbackTo("REGION"))
+	vs.Duration("TIMEOUT", "audit test")
+	vs.Duration("INTERVAL", "audit test", env.Default("1m"))
+
+	live := testGetter{
+		"SVC_TIMEOUT": "1m",
+		"SVC_NAME":    "a",
+		"SVC_TOKEN":   "s3cret",
+		"SVC_DB":      "postgres://user:pass@db/app",
+		"REGION":      "us",
+	}
+	if err := vs.Parse(live); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := vs.WriteDotenv(&buf, false); err != nil {
+		t.Fatalf("unexpected error from WriteDotenv: %v", err)
+	}
+
+	r, err := env.Audit(vs, live, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error from Audit: %v", err)
+	}
+	if !r.OK() {
+		t.Errorf("got %+v, expected no differences", r)
+	}
+}
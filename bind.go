@@ -0,0 +1,398 @@
+package env
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	durationType             = reflect.TypeOf(time.Duration(0))
+	valueInterface           = reflect.TypeOf((*Value)(nil)).Elem()
+	textUnmarshalerInterface = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Bind defines a variable in the set for each field of the struct pointed to by
+// target which has an env tag, so that Parse writes values directly into the struct:
+//
+//	var c struct {
+//		Addr    string        `env:"ADDR,required" usage:"listen address"`
+//		Timeout time.Duration `env:"TIMEOUT" default:"30s"`
+//		Token   string        `env:"TOKEN,required,secret"`
+//	}
+//	if err := env.Bind(vs, &c); err != nil { ... }
+//
+// The tag gives the variable name, followed by the options required (the variable
+// must be set, unless it has a default) and secret (see Secret).  Unlike variables
+// defined individually, variables without the required option are Optional.  The
+// default tag gives the Default of the variable, and the usage tag its usage string.
+//
+// Fields can be booleans, numbers, strings, time.Durations, slices of booleans,
+// numbers or strings (parsed as comma separated lists), or types whose pointer
+// implements Value or encoding.TextUnmarshaler.
+//
+// Other struct fields are bound to a sub-set (see VarSet.Sub) named by their tag, or
+// their field name converted to SNAKE_CASE, so that nested configuration has
+// hierarchical names: the field c.DB.Host tagged env:"HOST" in the set "app" is
+// APP_DB_HOST.  The fields of embedded structs are bound without a further prefix,
+// unless the embedded field is tagged with a name.  Pointers to structs are only
+// followed if the field is tagged, and nil pointers are allocated.  Bind returns an
+// error, and defines no variables, if target is not a pointer to a struct, a tagged
+// field cannot be bound, or a struct contains itself through tagged pointers.
+func Bind(vs *VarSet, target interface{}) error {
+	rv, err := structTarget("Bind", target)
+	if err != nil {
+		return err
+	}
+	return bindStruct(vs, rv, false)
+}
+
+// VarSetFromStruct returns a new variable set with given name (see NewVarSet) with a
+// variable for every exported field of the struct pointed to by target, in the same way
+// as Bind.  Fields without an env tag, or with a tag which gives no name, are named by
+// converting the field name from CamelCase to SNAKE_CASE (i.e. DBHost is DB_HOST), so
+// that structs shared with JSON or YAML decoding need no further tags.  Fields tagged
+// env:"-" are skipped.
+func VarSetFromStruct(name string, target interface{}) (*VarSet, error) {
+	rv, err := structTarget("VarSetFromStruct", target)
+	if err != nil {
+		return nil, err
+	}
+	vs := NewVarSet(name)
+	if err := bindStruct(vs, rv, true); err != nil {
+		return nil, err
+	}
+	return vs, nil
+}
+
+// structTarget returns the struct pointed to by target.
+func structTarget(fn string, target interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("env: %v target must be a non-nil pointer to a struct, got %T", fn, target)
+	}
+	return rv.Elem(), nil
+}
+
+// bindStruct defines variables in vs for the fields of the struct rv.  If infer is
+// true then every exported field is bound, and fields without a tag name are named
+// after the field, otherwise only tagged fields are bound.  No variables are defined
+// if any field cannot be bound.
+func bindStruct(vs *VarSet, rv reflect.Value, infer bool) error {
+	var defs []func()
+	if err := bindFields(func() *VarSet { return vs }, rv, infer, "", make(map[reflect.Type]bool), &defs); err != nil {
+		return err
+	}
+	for _, def := range defs {
+		def()
+	}
+	return nil
+}
+
+// bindFields appends to defs a function to define a variable in the set returned by
+// set for each field of the struct rv, where path is the name of rv in the struct
+// passed to bindStruct, for errors, and outer holds the types of the structs which
+// contain rv, to detect cycles.  Nested structs are bound to sub-sets.
+func bindFields(set func() *VarSet, rv reflect.Value, infer bool, path string, outer map[reflect.Type]bool, defs *[]func()) error {
+	outer[rv.Type()] = true
+	defer delete(outer, rv.Type())
+
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Type().Field(i)
+		tag, ok := f.Tag.Lookup("env")
+		nested := isNestedStruct(f.Type)
+		if tag == "-" || !ok && f.PkgPath != "" && !(nested && f.Anonymous) || !ok && !infer && !nested {
+			continue
+		}
+		if !ok && nested && f.Type.Kind() == reflect.Ptr {
+			// Untagged pointers may be recursive, or shared with other configuration.
+			continue
+		}
+		field := path + f.Name
+		if ok && f.PkgPath != "" {
+			return fmt.Errorf("env: field %v is unexported", field)
+		}
+
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+
+		if nested {
+			if len(parts) > 1 {
+				return fmt.Errorf("env: field %v is a struct and cannot have options", field)
+			}
+			if name == "" && !f.Anonymous {
+				name = snakeCase(f.Name)
+			}
+			fv := rv.Field(i)
+			if t := f.Type; outer[t] || t.Kind() == reflect.Ptr && outer[t.Elem()] {
+				return fmt.Errorf("env: field %v is a recursive %v", field, f.Type)
+			}
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					if !fv.CanSet() {
+						return fmt.Errorf("env: field %v is a nil pointer to an unexported type", field)
+					}
+					fv.Set(reflect.New(fv.Type().Elem()))
+				}
+				fv = fv.Elem()
+			}
+			sub := set
+			if name != "" {
+				sub = subSet(set, name)
+			}
+			if err := bindFields(sub, fv, infer, field+".", outer, defs); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if name == "" && infer {
+			name = snakeCase(f.Name)
+		}
+		if name == "" {
+			return fmt.Errorf("env: field %v has no variable name", field)
+		}
+
+		required := false
+		var opts []Option
+		for _, o := range parts[1:] {
+			switch o {
+			case "required":
+				required = true
+			case "secret":
+				opts = append(opts, Secret())
+			default:
+				return fmt.Errorf("env: field %v has unknown option %q", field, o)
+			}
+		}
+		if def, ok := f.Tag.Lookup("default"); ok {
+			opts = append(opts, Default(def))
+		} else if !required {
+			opts = append(opts, Optional())
+		}
+
+		value, err := fieldValue(rv.Field(i))
+		if err != nil {
+			return fmt.Errorf("env: field %v: %v", field, err)
+		}
+		usage := f.Tag.Get("usage")
+		*defs = append(*defs, func() { set().Var(value, name, usage, opts...) })
+	}
+	return nil
+}
+
+// subSet returns a function which returns the sub-set with given name of the set
+// returned by parent, creating it on first use.
+func subSet(parent func() *VarSet, name string) func() *VarSet {
+	var s *VarSet
+	return func() *VarSet {
+		if s == nil {
+			s = parent().Sub(name)
+		}
+		return s
+	}
+}
+
+// isNestedStruct reports whether t is a struct, or pointer to a struct, whose fields
+// are bound individually rather than as a single Value.
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	p := reflect.PtrTo(t)
+	return !p.Implements(valueInterface) && !p.Implements(textUnmarshalerInterface)
+}
+
+// snakeCase converts a CamelCase name to SNAKE_CASE, keeping acronyms together:
+// HTTPPort is HTTP_PORT and UserID is USER_ID.
+func snakeCase(x string) string {
+	rs := []rune(x)
+	var b strings.Builder
+	for i, r := range rs {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := rs[i-1]
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && i+1 < len(rs) && unicode.IsLower(rs[i+1]) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// fieldValue returns a Value which stores its value in the addressable field fv.
+func fieldValue(fv reflect.Value) (Value, error) {
+	p := fv.Addr()
+	if p.Type().Implements(valueInterface) {
+		return p.Interface().(Value), nil
+	}
+	if fv.Type() == durationType {
+		d := p.Interface().(*time.Duration)
+		return newDurationValue(*d, d), nil
+	}
+	if p.Type().Implements(textUnmarshalerInterface) {
+		return textValue{p.Interface().(encoding.TextUnmarshaler)}, nil
+	}
+
+	switch x := p.Interface().(type) {
+	case *string:
+		return newStringValue(*x, x), nil
+	case *bool:
+		return newBoolValue(*x, x), nil
+	case *int:
+		return newIntValue(*x, x), nil
+	}
+	if isKind(fv.Type()) || fv.Kind() == reflect.Slice && isKind(fv.Type().Elem()) {
+		return &kindValue{fv}, nil
+	}
+	return nil, errors.New("unsupported type " + fv.Type().String())
+}
+
+// isKind reports whether t is a boolean, numeric or string type supported by kindValue.
+func isKind(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// kindValue is a Value stored in a field of a boolean, numeric or string type, or a
+// slice of one, which is parsed as a comma separated list.
+type kindValue struct {
+	v reflect.Value
+}
+
+func (v *kindValue) Set(x string) error {
+	if v.v.Kind() != reflect.Slice {
+		return setKind(v.v, x)
+	}
+	items := splitList(x)
+	s := reflect.MakeSlice(v.v.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := setKind(s.Index(i), item); err != nil {
+			return err
+		}
+	}
+	v.v.Set(s)
+	return nil
+}
+
+// setKind parses x into rv, which must have a type for which isKind is true.
+func setKind(rv reflect.Value, x string) error {
+	t := rv.Type()
+	switch t.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(x)
+		if err != nil {
+			return err
+		}
+		rv.SetBool(b)
+	case reflect.String:
+		rv.SetString(x)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(x, 0, t.Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(x, 0, t.Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(x, t.Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(f)
+	}
+	return nil
+}
+
+func (v *kindValue) String() string {
+	if !v.v.IsValid() {
+		return ""
+	}
+	if v.v.Kind() != reflect.Slice {
+		return fmt.Sprint(v.v.Interface())
+	}
+	items := make([]string, v.v.Len())
+	for i := range items {
+		items[i] = fmt.Sprint(v.v.Index(i).Interface())
+	}
+	return strings.Join(items, ",")
+}
+
+func (v *kindValue) typeName() string { return v.v.Type().String() }
+
+func (v *kindValue) Constraint() string {
+	if v.v.Kind() == reflect.Slice {
+		return "comma separated list of " + kindConstraint(v.v.Type().Elem())
+	}
+	if v.v.Kind() == reflect.String {
+		return ""
+	}
+	return kindConstraint(v.v.Type())
+}
+
+func (v *kindValue) Example() string {
+	if v.v.Kind() == reflect.Slice {
+		x := kindExample(v.v.Type().Elem())
+		return x + "," + x
+	}
+	if v.v.Kind() == reflect.String {
+		return ""
+	}
+	return kindExample(v.v.Type())
+}
+
+// kindExample returns an example of a value accepted for type t.
+func kindExample(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "true"
+	case reflect.String:
+		return "a"
+	case reflect.Float32, reflect.Float64:
+		return "0.5"
+	}
+	return "10"
+}
+
+// kindConstraint describes the values accepted for type t.
+func kindConstraint(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprintf("%d-bit integer", t.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprintf("%d-bit unsigned integer", t.Bits())
+	}
+	return "number"
+}
+
+// ParseStruct defines variables in the default set for the tagged fields of the
+// struct pointed to by target (see Bind), and parses the environment into them.
+func ParseStruct(target interface{}, opts ...ParseOption) error {
+	if err := Bind(CmdVar, target); err != nil {
+		return err
+	}
+	return Parse(opts...)
+}
@@ -0,0 +1,332 @@
+package env_test
+
+import (
+	"net"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"code.sajari.com/env"
+)
+
+type bindConfig struct {
+	Addr    string          `env:"ADDR,required" usage:"listen address"`
+	Timeout time.Duration   `env:"TIMEOUT" default:"30s"`
+	Token   string          `env:"TOKEN,required,secret"`
+	Debug   bool            `env:"DEBUG"`
+	Workers int             `env:"WORKERS" default:"4"`
+	IP      net.IP          `env:"IP"`
+	Port    positiveInteger `env:"PORT"`
+	Ignored string
+	Skipped string `env:"-"`
+}
+
+func TestBind(t *testing.T) {
+	var c bindConfig
+	vs := env.NewVarSet("svc")
+	if err := env.Bind(vs, &c); err != nil {
+		t.Fatalf("unexpected error from Bind: %v", err)
+	}
+
+	in := testGetter{
+		"SVC_ADDR":  ":80",
+		"SVC_TOKEN": "s3cret",
+		"SVC_IP":    "10.0.0.1",
+		"SVC_PORT":  "8080",
+		"IGNORED":   "x",
+		"SKIPPED":   "x",
+	}
+	if err := vs.Parse(in); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+	expected := bindConfig{
+		Addr:    ":80",
+		Timeout: 30 * time.Second,
+		Token:   "s3cret",
+		Workers: 4,
+		IP:      net.ParseIP("10.0.0.1"),
+		Port:    8080,
+	}
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("got %+v, expected %+v", c, expected)
+	}
+
+	var secret bool
+	vs.Visit(func(x *env.Var) {
+		if x.Name == "SVC_TOKEN" {
+			secret = x.Secret()
+		}
+	})
+	if !secret {
+		t.Errorf("expected SVC_TOKEN to be secret")
+	}
+
+	delete(in, "SVC_ADDR")
+	if err := vs.Parse(in); err == nil {
+		t.Errorf("expected error for missing required variable")
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		target interface{}
+	}{
+		{"nil", nil},
+		{"not a pointer", bindConfig{}},
+		{"not a struct", new(string)},
+		{"unexported", &struct {
+			x string `env:"X"`
+		}{}},
+		{"no name", &struct {
+			X string `env:",required"`
+		}{}},
+		{"unknown option", &struct {
+			X string `env:"X,requred"`
+		}{}},
+		{"unsupported type", &struct {
+			X chan int `env:"X"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			if err := env.Bind(vs, tt.target); err == nil {
+				t.Errorf("expected error from Bind")
+			}
+			vs.Visit(func(x *env.Var) {
+				t.Errorf("unexpected variable %v defined", x.Name)
+			})
+		})
+	}
+}
+
+func TestParseStruct(t *testing.T) {
+	env.ResetForTesting()
+	defer env.ResetForTesting()
+
+	os.Setenv("TEST_BIND_NAME", "svc")
+	defer os.Unsetenv("TEST_BIND_NAME")
+
+	var c struct {
+		Name string `env:"BIND_NAME,required"`
+	}
+	if err := env.ParseStruct(&c); err != nil {
+		t.Fatalf("unexpected error from ParseStruct: %v", err)
+	}
+	if c.Name != "svc" {
+		t.Errorf("got %q, expected %q", c.Name, "svc")
+	}
+}
+
+func TestVarSetFromStruct(t *testing.T) {
+	var c struct {
+		DBHost     string
+		HTTPPort   uint16 `default:"8080"`
+		UserID     int64
+		Ratio      float32
+		Tags       []string
+		Ports      []int
+		RetryDelay time.Duration
+		IP         net.IP
+		Token      string `env:"API_TOKEN,required,secret"`
+		Ignored    string `env:"-"`
+		unexported string
+		MaxConns2  int8
+		Enabled    bool
+	}
+	vs, err := env.VarSetFromStruct("svc", &c)
+	if err != nil {
+		t.Fatalf("unexpected error from VarSetFromStruct: %v", err)
+	}
+
+	var names []string
+	vs.Visit(func(x *env.Var) { names = append(names, x.Name) })
+	expectedNames := []string{"SVC_DB_HOST", "SVC_HTTP_PORT", "SVC_USER_ID", "SVC_RATIO", "SVC_TAGS", "SVC_PORTS", "SVC_RETRY_DELAY", "SVC_IP", "SVC_API_TOKEN", "SVC_MAX_CONNS2", "SVC_ENABLED"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("got names %v, expected %v", names, expectedNames)
+	}
+
+	in := testGetter{
+		"SVC_DB_HOST":     "db",
+		"SVC_USER_ID":     "-12",
+		"SVC_RATIO":       "0.5",
+		"SVC_TAGS":        "a, b",
+		"SVC_PORTS":       "80,443",
+		"SVC_RETRY_DELAY": "2s",
+		"SVC_IP":          "10.0.0.1",
+		"SVC_API_TOKEN":   "t",
+		"SVC_MAX_CONNS2":  "0x10",
+		"SVC_ENABLED":     "true",
+	}
+	if err := vs.Parse(in); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+	if c.DBHost != "db" || c.HTTPPort != 8080 || c.UserID != -12 || c.Ratio != 0.5 ||
+		!reflect.DeepEqual(c.Tags, []string{"a", "b"}) || !reflect.DeepEqual(c.Ports, []int{80, 443}) ||
+		c.RetryDelay != 2*time.Second || !c.IP.Equal(net.ParseIP("10.0.0.1")) || c.Token != "t" ||
+		c.MaxConns2 != 16 || !c.Enabled {
+		t.Errorf("got %+v", c)
+	}
+
+	in["SVC_MAX_CONNS2"] = "200"
+	if err := vs.Parse(in); err == nil {
+		t.Errorf("expected error for out of range int8")
+	}
+
+	schema := vs.SchemaVersioned("")
+	for _, x := range schema.Vars {
+		if x.Name == "SVC_HTTP_PORT" && (x.Type != "uint16" || x.Constraint != "16-bit unsigned integer") {
+			t.Errorf("got type %q and constraint %q for %v", x.Type, x.Constraint, x.Name)
+		}
+	}
+
+	if _, err := env.VarSetFromStruct("", &struct{ C chan int }{}); err == nil {
+		t.Errorf("expected error for unsupported field type")
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	var c struct {
+		Name, DBHost, HTTPPort, UserID, MaxConns2, A, ABC, ServeHTTP int
+	}
+	vs, err := env.VarSetFromStruct("", &c)
+	if err != nil {
+		t.Fatalf("unexpected error from VarSetFromStruct: %v", err)
+	}
+	var names []string
+	vs.Visit(func(x *env.Var) { names = append(names, x.Name) })
+	expected := []string{"NAME", "DB_HOST", "HTTP_PORT", "USER_ID", "MAX_CONNS2", "A", "ABC", "SERVE_HTTP"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("got %v, expected %v", names, expected)
+	}
+}
+
+type bindDB struct {
+	Host string `env:"HOST,required"`
+	Port int    `env:"PORT" default:"5432"`
+}
+
+type bindCommon struct {
+	LogLevel string `env:"LOG_LEVEL" default:"info"`
+}
+
+type bindTLS struct {
+	Cert string `env:"CERT"`
+}
+
+func TestBindNested(t *testing.T) {
+	var c struct {
+		bindCommon
+		DB      bindDB
+		Replica *bindDB  `env:"DB_REPLICA"`
+		TLS     *bindTLS `env:"TLS"`
+		Skipped *bindTLS
+		Cache   struct {
+			Size  int `env:"SIZE" default:"10"`
+			Redis struct {
+				Addr string `env:"ADDR"`
+			}
+		}
+	}
+
+	vs := env.NewVarSet("app")
+	if err := env.Bind(vs, &c); err != nil {
+		t.Fatalf("unexpected error from Bind: %v", err)
+	}
+	if c.Replica == nil || c.TLS == nil {
+		t.Fatalf("expected nil pointers to structs to be allocated")
+	}
+	if c.Skipped != nil {
+		t.Errorf("expected untagged pointer to struct to be skipped")
+	}
+
+	var names []string
+	vs.Visit(func(x *env.Var) { names = append(names, x.Name) })
+	expectedNames := []string{
+		"APP_LOG_LEVEL",
+		"APP_DB_HOST", "APP_DB_PORT",
+		"APP_DB_REPLICA_HOST", "APP_DB_REPLICA_PORT",
+		"APP_TLS_CERT",
+		"APP_CACHE_SIZE",
+		"APP_CACHE_REDIS_ADDR",
+	}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("got names %v, expected %v", names, expectedNames)
+	}
+
+	in := testGetter{
+		"APP_DB_HOST":          "db",
+		"APP_DB_REPLICA_HOST":  "replica",
+		"APP_DB_REPLICA_PORT":  "6543",
+		"APP_CACHE_REDIS_ADDR": "redis:6379",
+	}
+	if err := vs.Parse(in); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+	if c.LogLevel != "info" || c.DB != (bindDB{"db", 5432}) || *c.Replica != (bindDB{"replica", 6543}) ||
+		c.Cache.Size != 10 || c.Cache.Redis.Addr != "redis:6379" {
+		t.Errorf("got %+v", c)
+	}
+}
+
+func TestVarSetFromStructNested(t *testing.T) {
+	var c struct {
+		Server struct {
+			HTTPPort int
+		}
+		Limits *struct {
+			MaxConns int `env:"CONNS"`
+		} `env:""`
+	}
+	vs, err := env.VarSetFromStruct("", &c)
+	if err != nil {
+		t.Fatalf("unexpected error from VarSetFromStruct: %v", err)
+	}
+	var names []string
+	vs.Visit(func(x *env.Var) { names = append(names, x.Name) })
+	expected := []string{"SERVER_HTTP_PORT", "LIMITS_CONNS"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("got names %v, expected %v", names, expected)
+	}
+
+	var bad struct {
+		DB struct {
+			C chan int `env:"C"`
+		}
+	}
+	if _, err := env.VarSetFromStruct("", &bad); err == nil || err.Error() != "env: field DB.C: unsupported type chan int" {
+		t.Errorf("got error %v, expected unsupported type for DB.C", err)
+	}
+}
+
+type bindNode struct {
+	Name   string    `env:"NAME"`
+	Parent *bindNode `env:"PARENT"`
+	Next   *bindNode
+}
+
+func TestBindRecursive(t *testing.T) {
+	var n bindNode
+	if err := env.Bind(env.NewVarSet("app"), &n); err == nil || err.Error() != "env: field Parent is a recursive *env_test.bindNode" {
+		t.Errorf("got error %v, expected recursive Parent", err)
+	}
+
+	var c struct {
+		Node struct {
+			Name string
+			Next *bindNode
+		}
+	}
+	vs, err := env.VarSetFromStruct("", &c)
+	if err != nil {
+		t.Fatalf("unexpected error from VarSetFromStruct: %v", err)
+	}
+	var names []string
+	vs.Visit(func(x *env.Var) { names = append(names, x.Name) })
+	if expected := []string{"NODE_NAME"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("got names %v, expected %v", names, expected)
+	}
+}
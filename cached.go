@@ -0,0 +1,89 @@
+package env
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CachedGetter is a Getter which memoizes the lookups of another Getter (see Cached).
+type CachedGetter struct {
+	g     Getter
+	ttl   time.Duration
+	clock Clock
+
+	mu      sync.Mutex
+	entries map[string]cachedEntry
+}
+
+type cachedEntry struct {
+	value   string
+	ok      bool
+	expires time.Time // zero if the entry does not expire
+}
+
+// Get implements Getter.
+func (c *CachedGetter) Get(x string) (string, bool) {
+	z, ok, _ := c.GetContext(context.Background(), x)
+	return z, ok
+}
+
+// GetContext implements ContextGetter.  Errors from the underlying Getter are not cached.
+func (c *CachedGetter) GetContext(ctx context.Context, x string) (string, bool, error) {
+	now := c.now()
+
+	c.mu.Lock()
+	e, hit := c.entries[x]
+	c.mu.Unlock()
+	if hit && (e.expires.IsZero() || now.Before(e.expires)) {
+		return e.value, e.ok, nil
+	}
+
+	z, ok, err := getContext(ctx, c.g, x)
+	if err != nil {
+		return "", false, err
+	}
+
+	e = cachedEntry{value: z, ok: ok}
+	if c.ttl > 0 {
+		e.expires = now.Add(c.ttl)
+	}
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]cachedEntry)
+	}
+	c.entries[x] = e
+	c.mu.Unlock()
+	return z, ok, nil
+}
+
+// Reset discards all cached lookups.
+func (c *CachedGetter) Reset() {
+	c.mu.Lock()
+	c.entries = nil
+	c.mu.Unlock()
+}
+
+func (c *CachedGetter) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
+// Cached returns a Getter which memoizes the lookups of g for ttl, or until it is
+// reset if ttl is zero, so that repeated calls to Parse (i.e. on reload) do not
+// repeatedly query a remote store.  Unset variables are cached too.
+//
+// The returned Getter is also a ContextGetter: errors from g are returned and not
+// cached.  Call Reset to discard all cached lookups.
+func Cached(g Getter, ttl time.Duration) *CachedGetter {
+	return &CachedGetter{g: g, ttl: ttl}
+}
+
+// CachedClock returns a Getter which memoizes the lookups of g in the same way as
+// Cached, but uses c to expire cached lookups, so that expiry can be controlled by the
+// same Clock as the VarSet (see VarSet.SetClock).  If c is nil the system clock is used.
+func CachedClock(g Getter, ttl time.Duration, c Clock) *CachedGetter {
+	return &CachedGetter{g: g, ttl: ttl, clock: c}
+}
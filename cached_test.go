@@ -0,0 +1,74 @@
+package env_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"code.sajari.com/env"
+)
+
+// countingGetter is a ContextGetter which counts lookups, and fails for names in errs.
+type countingGetter struct {
+	testGetter
+	errs  map[string]error
+	count int
+}
+
+func (g *countingGetter) Get(x string) (string, bool) {
+	z, ok, _ := g.GetContext(context.Background(), x)
+	return z, ok
+}
+
+func (g *countingGetter) GetContext(ctx context.Context, x string) (string, bool, error) {
+	g.count++
+	if err := g.errs[x]; err != nil {
+		return "", false, err
+	}
+	z, ok := g.testGetter.Get(x)
+	return z, ok, nil
+}
+
+func TestCached(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	remote := &countingGetter{
+		testGetter: testGetter{"A": "1"},
+		errs:       map[string]error{"BROKEN": errors.New("unavailable")},
+	}
+	g := env.CachedClock(remote, time.Minute, env.ClockFunc(func() time.Time { return now }))
+
+	for i := 0; i < 3; i++ {
+		if z, ok := g.Get("A"); !ok || z != "1" {
+			t.Errorf("g.Get(\"A\") = (%q, %v), expected (\"1\", true)", z, ok)
+		}
+		if z, ok := g.Get("UNSET"); ok {
+			t.Errorf("g.Get(\"UNSET\") = (%q, %v), expected unset", z, ok)
+		}
+	}
+	if remote.count != 2 {
+		t.Errorf("got %d lookups, expected 2", remote.count)
+	}
+
+	now = now.Add(time.Minute)
+	remote.testGetter["A"] = "2"
+	if z, ok := g.Get("A"); !ok || z != "2" {
+		t.Errorf("g.Get(\"A\") = (%q, %v) after expiry, expected (\"2\", true)", z, ok)
+	}
+
+	var cg env.ContextGetter = g
+	for i := 0; i < 2; i++ {
+		if _, _, err := cg.GetContext(context.Background(), "BROKEN"); err == nil {
+			t.Errorf("expected error from GetContext")
+		}
+	}
+	if remote.count != 5 {
+		t.Errorf("got %d lookups, expected errors not to be cached", remote.count)
+	}
+
+	remote.testGetter["A"] = "3"
+	g.Reset()
+	if z, _ := g.Get("A"); z != "3" {
+		t.Errorf("g.Get(\"A\") = %q after Reset, expected \"3\"", z)
+	}
+}
@@ -0,0 +1,126 @@
+package env
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+type certValue struct {
+	p      *[]*x509.Certificate
+	raw    string
+	window time.Duration
+	now    func() time.Time
+}
+
+func (v *certValue) Set(x string) error {
+	return v.setWarn(x, func(string) {})
+}
+
+// setWarn implements warnSetter: certificates which expire within the window are
+// reported to warn, rather than failing.
+func (v *certValue) setWarn(x string, warn func(string)) error {
+	data := []byte(x)
+	if !strings.HasPrefix(strings.TrimSpace(x), "-----BEGIN") {
+		b, err := ioutil.ReadFile(x)
+		if err != nil {
+			return err
+		}
+		data = b
+	}
+
+	certs, err := parseCertificates(data)
+	if err != nil {
+		return err
+	}
+	now := v.now()
+	if err := checkCertificates(certs, now); err != nil {
+		return err
+	}
+	for _, c := range certs {
+		if now.Add(v.window).After(c.NotAfter) {
+			warn(fmt.Sprintf("certificate %q expires at %v, within %v", c.Subject.CommonName, c.NotAfter, v.window))
+		}
+	}
+	*v.p = certs
+	v.raw = x
+	return nil
+}
+
+func (v *certValue) String() string {
+	return v.raw
+}
+
+func (v *certValue) typeName() string { return "certificates" }
+
+func (v *certValue) Constraint() string {
+	if v.window > 0 {
+		return fmt.Sprintf("PEM certificates (path or inline), valid for at least %v", v.window)
+	}
+	return "PEM certificates (path or inline), currently valid"
+}
+
+func (v *certValue) Example() string { return "/path/to/cert.pem" }
+
+// parseCertificates parses all the CERTIFICATE blocks in PEM encoded data.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var b *pem.Block
+		b, data = pem.Decode(data)
+		if b == nil {
+			break
+		}
+		if b.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(b.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, c)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM certificates found")
+	}
+	return certs, nil
+}
+
+// checkCertificates checks that each of the certificates is valid at now.
+func checkCertificates(certs []*x509.Certificate, now time.Time) error {
+	for _, c := range certs {
+		switch {
+		case now.Before(c.NotBefore):
+			return fmt.Errorf("certificate %q is not valid until %v", c.Subject.CommonName, c.NotBefore)
+		case now.After(c.NotAfter):
+			return fmt.Errorf("certificate %q expired at %v", c.Subject.CommonName, c.NotAfter)
+		}
+	}
+	return nil
+}
+
+// Certificate defines a variable with specified name and usage string holding PEM
+// encoded X.509 certificates, given either inline or as a path to a PEM file.
+//
+// Parsing fails if any certificate is not yet valid or has expired at the current time
+// (as given by the set's Clock, see SetClock).  Parse emits a warning (see OnWarning)
+// for any certificate which expires within window of the current time.
+// The return value is the address of a slice which stores the parsed certificates.
+func (v *VarSet) Certificate(name, usage string, window time.Duration, opts ...Option) *[]*x509.Certificate {
+	p := new([]*x509.Certificate)
+	v.Var(&certValue{p: p, window: window, now: v.now}, name, usage, opts...)
+	return p
+}
+
+// Certificate defines a variable with specified name and usage string holding PEM
+// encoded X.509 certificates, given either inline or as a path to a PEM file.
+// Parsing fails if any certificate is not currently valid, and warns if any expires
+// within window.
+// The return value is the address of a slice which stores the parsed certificates.
+func Certificate(name, usage string, window time.Duration, opts ...Option) *[]*x509.Certificate {
+	return CmdVar.Certificate(name, usage, window, opts...)
+}
@@ -0,0 +1,92 @@
+package env_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"code.sajari.com/env"
+)
+
+var certNotBefore = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+var certNotAfter = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func testCertificatePEM(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    certNotBefore,
+		NotAfter:     certNotAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("could not create certificate: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestCertificate(t *testing.T) {
+	certPEM := testCertificatePEM(t)
+
+	tmpFile, err := ioutil.TempFile("", "Certificate")
+	if err != nil {
+		t.Fatalf("could not create temporary file: %v", err)
+	}
+	defer tmpFile.Close()
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.WriteString(certPEM); err != nil {
+		t.Fatalf("could not write temporary file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		in      string
+		now     time.Time
+		window  time.Duration
+		wantErr bool
+		warning bool
+	}{
+		{"inline", certPEM, certNotBefore.Add(time.Hour), 0, false, false},
+		{"path", tmpFile.Name(), certNotBefore.Add(time.Hour), 0, false, false},
+		{"window", certPEM, certNotAfter.Add(-48 * time.Hour), 24 * time.Hour, false, false},
+		{"expiring", certPEM, certNotAfter.Add(-time.Hour), 24 * time.Hour, false, true},
+
+		{"not yet valid", certPEM, certNotBefore.Add(-time.Hour), 0, true, false},
+		{"expired", certPEM, certNotAfter.Add(time.Hour), 0, true, false},
+		{"missing file", "filedoesnotexist.pem", certNotBefore.Add(time.Hour), 0, true, false},
+		{"not pem", "-----BEGIN nothing", certNotBefore.Add(time.Hour), 0, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			now := tt.now
+			vs.SetClock(env.ClockFunc(func() time.Time { return now }))
+			certs := vs.Certificate("CERT", "certificate test", tt.window)
+
+			var warnings []string
+			onWarning := env.OnWarning(func(msg string) { warnings = append(warnings, msg) })
+			if err := vs.Parse(testGetter{"CERT": tt.in}, onWarning); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (len(warnings) != 0) != tt.warning {
+				t.Errorf("got warnings %q, expected warning %v", warnings, tt.warning)
+			}
+			if !tt.wantErr && len(*certs) != 1 {
+				t.Errorf("len(*certs) = %d, expected 1", len(*certs))
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package env
+
+import "context"
+
+// chainGetter is a Getter which tries each of its Getters in order.
+type chainGetter []Getter
+
+func (c chainGetter) Get(x string) (string, bool) {
+	for _, g := range c {
+		if z, ok := g.Get(x); ok {
+			return z, true
+		}
+	}
+	return "", false
+}
+
+func (c chainGetter) GetContext(ctx context.Context, x string) (string, bool, error) {
+	for _, g := range c {
+		if cg, ok := g.(ContextGetter); ok {
+			z, ok, err := cg.GetContext(ctx, x)
+			if err != nil {
+				return "", false, err
+			}
+			if ok {
+				return z, true, nil
+			}
+			continue
+		}
+		if z, ok := g.Get(x); ok {
+			return z, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// Chain returns a Getter which looks up variables in each of getters in order, and
+// returns the first value found.  Earlier Getters take precedence over later ones,
+// so the most specific sources should be given first:
+//
+//	g := env.Chain(env.OS(), dotenv, defaults)
+//
+// The returned Getter is also a ContextGetter: a variable is only looked up in later
+// Getters if it is unset in earlier ones, and errors from ContextGetters stop the
+// lookup.
+func Chain(getters ...Getter) Getter {
+	return chainGetter(getters)
+}
@@ -0,0 +1,49 @@
+package env_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestChain(t *testing.T) {
+	g := env.Chain(
+		testGetter{"A": "1"},
+		testGetter{"A": "2", "B": "2", "EMPTY": ""},
+		testGetter{"B": "3", "C": "3", "EMPTY": "3"},
+	)
+
+	tests := []struct {
+		name string
+		out  string
+		ok   bool
+	}{
+		{"A", "1", true},
+		{"B", "2", true},
+		{"C", "3", true},
+		{"EMPTY", "", true},
+		{"D", "", false},
+	}
+
+	for _, tt := range tests {
+		if z, ok := g.Get(tt.name); z != tt.out || ok != tt.ok {
+			t.Errorf("g.Get(%q) = (%q, %v), expected (%q, %v)", tt.name, z, ok, tt.out, tt.ok)
+		}
+	}
+
+	errUnavailable := errors.New("unavailable")
+	g = env.Chain(
+		testGetter{"A": "1"},
+		remoteGetter{testGetter: testGetter{"B": "2"}, errs: map[string]error{"C": errUnavailable}},
+		testGetter{"C": "3"},
+	)
+	cg := g.(env.ContextGetter)
+	if z, ok, err := cg.GetContext(context.Background(), "B"); z != "2" || !ok || err != nil {
+		t.Errorf("cg.GetContext(\"B\") = (%q, %v, %v), expected (\"2\", true, nil)", z, ok, err)
+	}
+	if _, _, err := cg.GetContext(context.Background(), "C"); err != errUnavailable {
+		t.Errorf("cg.GetContext(\"C\") error = %v, expected %v", err, errUnavailable)
+	}
+}
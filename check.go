@@ -2,8 +2,10 @@ package env
 
 import (
 	"errors"
+	"fmt"
 	"net"
 	"os"
+	"strings"
 )
 
 // checkedValue wraps a Value and runs fn on any values passed to Set
@@ -11,6 +13,10 @@ import (
 type checkedValue struct {
 	fn func(string) error
 
+	constraint string                 // description of the check made by fn
+	example    string                 // example value which passes the check
+	schema     map[string]interface{} // JSON Schema keywords for values which pass the check, optional
+
 	Value
 }
 
@@ -21,6 +27,21 @@ func (v checkedValue) Set(x string) error {
 	return v.Value.Set(x)
 }
 
+func (v checkedValue) Constraint() string { return v.constraint }
+func (v checkedValue) Example() string    { return v.example }
+
+// isOneOf returns a check that x is one of values.
+func isOneOf(values ...string) func(string) error {
+	return func(x string) error {
+		for _, v := range values {
+			if x == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of %v", x, strings.Join(values, ", "))
+	}
+}
+
 // isNonEmpty checks if x is a non-empty string.
 func isNonEmpty(x string) error {
 	if x == "" {
@@ -62,8 +83,205 @@ func isDialAddr(x string) error {
 	return nil
 }
 
+// isHostname checks if x is a valid RFC 1123 hostname.
+//
+// A valid hostname is at most 253 characters of dot separated labels,
+// each of which is 1 to 63 letters, digits or hyphens and does not begin
+// or end with a hyphen.
+func isHostname(x string) error {
+	if x == "" {
+		return errors.New("empty hostname")
+	}
+	if len(x) > 253 {
+		return errors.New("hostname longer than 253 characters")
+	}
+	for _, label := range strings.Split(x, ".") {
+		if label == "" {
+			return errors.New("empty label in hostname")
+		}
+		if len(label) > 63 {
+			return fmt.Errorf("label %q longer than 63 characters", label)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("label %q begins or ends with a hyphen", label)
+		}
+		for _, r := range label {
+			if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '-') {
+				return fmt.Errorf("invalid character %q in hostname", r)
+			}
+		}
+	}
+	return nil
+}
+
+// isLanguageTag checks if x is a syntactically valid BCP 47 language tag,
+// i.e. "en", "en-AU" or "zh-Hant-TW".
+//
+// Only the syntax of the tag is checked, subtags are not validated against
+// the IANA Language Subtag Registry.
+func isLanguageTag(x string) error {
+	if x == "" {
+		return errors.New("empty language tag")
+	}
+	subtags := strings.Split(x, "-")
+	for _, s := range subtags {
+		if s == "" || len(s) > 8 || !isAlphanumeric(s) {
+			return fmt.Errorf("invalid subtag %q", s)
+		}
+	}
+
+	// Private use tag, i.e. "x-whatever".
+	if strings.EqualFold(subtags[0], "x") {
+		return checkPrivateUse(subtags[1:])
+	}
+
+	i := 0
+	next := func() string {
+		if i < len(subtags) {
+			return subtags[i]
+		}
+		return ""
+	}
+
+	// Primary language subtag, with optional extended language subtags.
+	lang := next()
+	if !isAlpha(lang) || len(lang) < 2 {
+		return fmt.Errorf("invalid language subtag %q", lang)
+	}
+	i++
+	if len(lang) <= 3 {
+		for n := 0; n < 3 && len(next()) == 3 && isAlpha(next()); n++ {
+			i++
+		}
+	}
+
+	// Script subtag.
+	if s := next(); len(s) == 4 && isAlpha(s) {
+		i++
+	}
+
+	// Region subtag.
+	if s := next(); len(s) == 2 && isAlpha(s) || len(s) == 3 && isDigits(s) {
+		i++
+	}
+
+	// Variant subtags.
+	for {
+		s := next()
+		if len(s) >= 5 || len(s) == 4 && '0' <= s[0] && s[0] <= '9' {
+			i++
+			continue
+		}
+		break
+	}
+
+	// Extension subtags.
+	singletons := make(map[string]bool)
+	for {
+		s := strings.ToLower(next())
+		if len(s) != 1 || s == "x" {
+			break
+		}
+		if singletons[s] {
+			return fmt.Errorf("duplicate extension %q", s)
+		}
+		singletons[s] = true
+		i++
+		n := 0
+		for len(next()) >= 2 {
+			i++
+			n++
+		}
+		if n == 0 {
+			return fmt.Errorf("empty extension %q", s)
+		}
+	}
+
+	if s := next(); strings.EqualFold(s, "x") {
+		return checkPrivateUse(subtags[i+1:])
+	}
+	if i < len(subtags) {
+		return fmt.Errorf("invalid subtag %q", subtags[i])
+	}
+	return nil
+}
+
+// checkPrivateUse checks the subtags following an "x" singleton.
+func checkPrivateUse(subtags []string) error {
+	if len(subtags) == 0 {
+		return errors.New("empty private use subtag")
+	}
+	return nil
+}
+
+func isAlpha(x string) bool {
+	for _, r := range x {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') {
+			return false
+		}
+	}
+	return x != ""
+}
+
+func isDigits(x string) bool {
+	for _, r := range x {
+		if !('0' <= r && r <= '9') {
+			return false
+		}
+	}
+	return x != ""
+}
+
+func isAlphanumeric(x string) bool {
+	for _, r := range x {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9') {
+			return false
+		}
+	}
+	return x != ""
+}
+
 // isPath checks if x is a valid path.
 func isPath(x string) error {
 	_, err := os.Stat(x)
 	return err
 }
+
+// isFile checks if x is a path to an existing regular file.
+func isFile(x string) error {
+	fi, err := os.Stat(x)
+	if err != nil {
+		return err
+	}
+	if !fi.Mode().IsRegular() {
+		return errors.New("not a regular file")
+	}
+	return nil
+}
+
+// isDir checks if x is a path to an existing directory.
+func isDir(x string) error {
+	fi, err := os.Stat(x)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return errors.New("not a directory")
+	}
+	return nil
+}
+
+// makeDir returns a check which creates the directory x (along with any
+// necessary parents) with permissions perm if it does not already exist,
+// then checks that x is a directory.
+func makeDir(perm os.FileMode) func(string) error {
+	return func(x string) error {
+		if x == "" {
+			return errors.New("empty")
+		}
+		if err := os.MkdirAll(x, perm); err != nil {
+			return err
+		}
+		return isDir(x)
+	}
+}
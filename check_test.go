@@ -3,6 +3,7 @@ package env_test
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -157,6 +158,90 @@ func TestIsDialAddr(t *testing.T) {
 	}
 }
 
+func TestIsHostname(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		// Valid
+		{"localhost", false},
+		{"sajari.com", false},
+		{"a-b.example.com", false},
+		{"1.example.com", false},
+		{strings.Repeat("a", 63) + ".com", false},
+
+		// Invalid
+		{"", true},
+		{".", true},
+		{"sajari.com.", true},
+		{"a..b", true},
+		{"-a.com", true},
+		{"a-.com", true},
+		{"a_b.com", true},
+		{"localhost:1234", true},
+		{strings.Repeat("a", 64) + ".com", true},
+		{strings.Repeat("a.", 127) + "a", true},
+	}
+
+	env.ResetForTesting()
+	prefix := env.CmdVar.Name()
+	_ = env.Hostname("HOST", "hostname test")
+	name := strings.ToUpper(prefix) + "_HOST"
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			os.Setenv(name, tt.in)
+
+			if err := env.Parse(); (err != nil) != tt.wantErr {
+				t.Errorf("env.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsLanguageTag(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		// Valid
+		{"en", false},
+		{"en-AU", false},
+		{"zh-Hant-TW", false},
+		{"es-419", false},
+		{"sl-rozaj-biske", false},
+		{"de-CH-1901", false},
+		{"zh-yue-HK", false},
+		{"en-US-u-ca-gregory", false},
+		{"en-a-bbb-x-a-ccc", false},
+		{"x-whatever", false},
+		{"qaa-Qaaa-QM-x-southern", false},
+
+		// Invalid
+		{"", true},
+		{"e", true},
+		{"en-", true},
+		{"en_AU", true},
+		{"1en", true},
+		{"en-AU-a", true},
+		{"en-a-bbb-a-ccc", true},
+		{"en-x", true},
+		{"abcdefghi", true},
+		{"en-AU-AU", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			vs.LanguageTag("LANG", "language tag test")
+
+			if err := vs.Parse(testGetter{"LANG": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestIsPath(t *testing.T) {
 	env.ResetForTesting()
 
@@ -192,3 +277,306 @@ func TestMissingGetter(t *testing.T) {
 		t.Errorf("expected error for missing var")
 	}
 }
+
+func TestIsFile(t *testing.T) {
+	env.ResetForTesting()
+
+	tmpFile, err := ioutil.TempFile("", "IsFile")
+	if err != nil {
+		t.Fatalf("could not create temporary file: %v", err)
+	}
+	defer tmpFile.Close()
+	defer os.Remove(tmpFile.Name())
+
+	prefix := env.CmdVar.Name()
+	_ = env.File("FILE", "file test")
+	name := strings.ToUpper(prefix) + "_FILE"
+
+	os.Setenv(name, tmpFile.Name())
+	if err := env.Parse(); err != nil {
+		t.Errorf("env.Parse() = %v, expected nil error", err)
+	}
+
+	os.Setenv(name, os.TempDir())
+	if err := env.Parse(); err == nil {
+		t.Error("env.Parse() should return an error for a directory")
+	}
+
+	os.Setenv(name, "filedoesnotexist.txt")
+	if err := env.Parse(); err == nil {
+		t.Error("env.Parse() should return an error")
+	}
+}
+
+func TestIsDir(t *testing.T) {
+	env.ResetForTesting()
+
+	tmpDir, err := ioutil.TempDir("", "IsDir")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpFile, err := ioutil.TempFile(tmpDir, "IsDir")
+	if err != nil {
+		t.Fatalf("could not create temporary file: %v", err)
+	}
+	defer tmpFile.Close()
+
+	prefix := env.CmdVar.Name()
+	_ = env.Dir("DIR", "dir test")
+	name := strings.ToUpper(prefix) + "_DIR"
+
+	os.Setenv(name, tmpDir)
+	if err := env.Parse(); err != nil {
+		t.Errorf("env.Parse() = %v, expected nil error", err)
+	}
+
+	os.Setenv(name, tmpFile.Name())
+	if err := env.Parse(); err == nil {
+		t.Error("env.Parse() should return an error for a file")
+	}
+
+	os.Setenv(name, filepath.Join(tmpDir, "dirdoesnotexist"))
+	if err := env.Parse(); err == nil {
+		t.Error("env.Parse() should return an error")
+	}
+}
+
+func TestDirCreate(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "DirCreate")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	vs := env.NewVarSet("")
+	dir := vs.DirCreate("DIR", "dir create test", 0755)
+
+	want := filepath.Join(tmpDir, "a", "b")
+	if err := vs.Parse(testGetter{"DIR": want}); err != nil {
+		t.Fatalf("vs.Parse() = %v, expected nil error", err)
+	}
+	if *dir != want {
+		t.Errorf("*dir = %q, expected %q", *dir, want)
+	}
+	if fi, err := os.Stat(want); err != nil || !fi.IsDir() {
+		t.Errorf("expected directory %v to be created: %v", want, err)
+	}
+
+	if err := vs.Parse(testGetter{"DIR": ""}); err == nil {
+		t.Error("vs.Parse() should return an error for an empty path")
+	}
+}
+
+func TestLocation(t *testing.T) {
+	tests := []struct {
+		in      string
+		out     string
+		wantErr bool
+	}{
+		// Valid
+		{"UTC", "UTC", false},
+		{"America/New_York", "America/New_York", false},
+
+		// Invalid
+		{"", "UTC", true},
+		{"Not/AZone", "UTC", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			loc := vs.Location("TZ", "location test")
+
+			if err := vs.Parse(testGetter{"TZ": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if s := (*loc).String(); s != tt.out {
+				t.Errorf(" = %v, expected %v", s, tt.out)
+			}
+		})
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		in       string
+		fraction bool
+		out      float64
+		wantErr  bool
+	}{
+		// Valid
+		{"25%", false, 25, false},
+		{"25", false, 25, false},
+		{"0.25", false, 0.25, false},
+		{"100", false, 100, false},
+		{"0", false, 0, false},
+		{"25%", true, 25, false},
+		{"0.25", true, 25, false},
+		{"1", true, 100, false},
+		{" 12.5 % ", true, 12.5, false},
+
+		// Invalid
+		{"", false, 0, true},
+		{"%", false, 0, true},
+		{"a", false, 0, true},
+		{"101", false, 0, true},
+		{"-1%", false, 0, true},
+		{"25", true, 0, true},
+		{"NaN", false, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			var p *float64
+			if tt.fraction {
+				p = vs.PercentFraction("PERCENT", "percent test")
+			} else {
+				p = vs.Percent("PERCENT", "percent test")
+			}
+
+			if err := vs.Parse(testGetter{"PERCENT": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if *p != tt.out {
+				t.Errorf(" = %v, expected %v", *p, tt.out)
+			}
+		})
+	}
+}
+
+func TestFileMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		out     os.FileMode
+		wantErr bool
+	}{
+		// Valid
+		{"0640", 0640, false},
+		{"640", 0640, false},
+		{"0", 0, false},
+		{"0777", 0777, false},
+		{"2775", os.ModeSetgid | 0775, false},
+		{"1777", os.ModeSticky | 0777, false},
+		{"4755", os.ModeSetuid | 0755, false},
+
+		// Invalid
+		{"", 0, true},
+		{"0648", 0, true},
+		{"rwxr-xr-x", 0, true},
+		{"-0640", 0, true},
+		{"10000", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			m := vs.FileMode("MODE", "file mode test")
+
+			if err := vs.Parse(testGetter{"MODE": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if *m != tt.out {
+				t.Errorf(" = %v, expected %v", *m, tt.out)
+			}
+		})
+	}
+}
+
+func TestMAC(t *testing.T) {
+	tests := []struct {
+		in      string
+		out     string
+		wantErr bool
+	}{
+		// Valid
+		{"00:00:5e:00:53:01", "00:00:5e:00:53:01", false},
+		{"00-00-5E-00-53-01", "00:00:5e:00:53:01", false},
+		{"0000.5e00.5301", "00:00:5e:00:53:01", false},
+
+		// Invalid
+		{"", "", true},
+		{"00:00:5e:00:53", "", true},
+		{"00:00:5e:00:53:zz", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			m := vs.MAC("MAC", "mac test")
+
+			if err := vs.Parse(testGetter{"MAC": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if s := m.String(); s != tt.out {
+				t.Errorf(" = %v, expected %v", s, tt.out)
+			}
+		})
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	tests := []struct {
+		in      string
+		base    int
+		out     string
+		wantErr bool
+	}{
+		// Valid
+		{"123456789012345678901234567890", 10, "123456789012345678901234567890", false},
+		{"-42", 10, "-42", false},
+		{"ff", 16, "255", false},
+		{"0xff", 0, "255", false},
+		{"0b1010", 0, "10", false},
+		{"0o17", 0, "15", false},
+		{"42", 0, "42", false},
+
+		// Invalid
+		{"", 10, "", true},
+		{"0xff", 10, "", true},
+		{"12.5", 10, "", true},
+		{"0b102", 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			n := vs.BigInt("BIGINT", "big int test", tt.base)
+
+			if err := vs.Parse(testGetter{"BIGINT": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var s string
+			if *n != nil {
+				s = (*n).String()
+			}
+			if s != tt.out {
+				t.Errorf(" = %v, expected %v", s, tt.out)
+			}
+		})
+	}
+}
+
+func TestBigIntBase(t *testing.T) {
+	for _, base := range []int{1, -1, 63} {
+		func() {
+			defer func() {
+				r := recover()
+				if msg, ok := r.(string); !ok || !strings.Contains(msg, "invalid BigInt base") {
+					t.Errorf("base %d: got panic %v, expected invalid base", base, r)
+				}
+			}()
+			env.NewVarSet("").BigInt("BIGINT", "big int test", base)
+		}()
+	}
+	for _, base := range []int{0, 2, 62} {
+		env.NewVarSet("").BigInt("BIGINT", "big int test", base)
+	}
+}
@@ -0,0 +1,36 @@
+package env
+
+import "time"
+
+// Clock provides the current time to time-dependent validations, such as
+// certificate expiry checks.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// ClockFunc is an adapter to allow the use of ordinary functions as a Clock.
+type ClockFunc func() time.Time
+
+// Now implements Clock.
+func (f ClockFunc) Now() time.Time { return f() }
+
+// SetClock sets the clock used by time-dependent validations of variables
+// in the set.  By default the system clock is used.
+//
+// Tests can set a fixed clock to check validations at a frozen time.
+func (v *VarSet) SetClock(c Clock) {
+	v.clock = c
+}
+
+// now returns the current time according to the set's clock, or that of
+// its parent if it was added using Sub or AddVarSet and has no clock of its own.
+func (v *VarSet) now() time.Time {
+	if v.clock == nil {
+		if v.parent != nil {
+			return v.parent.now()
+		}
+		return time.Now()
+	}
+	return v.clock.Now()
+}
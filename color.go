@@ -0,0 +1,124 @@
+package env
+
+import (
+	"fmt"
+	"os"
+)
+
+// Color is a mode controlling the use of color in terminal output.
+type Color int
+
+// Color modes.
+const (
+	// ColorAuto uses color when writing to a terminal, unless disabled by the
+	// NO_COLOR or CLICOLOR conventions.
+	ColorAuto Color = iota
+
+	// ColorAlways always uses color.
+	ColorAlways
+
+	// ColorNever never uses color.
+	ColorNever
+)
+
+// String implements fmt.Stringer.
+func (m Color) String() string {
+	switch m {
+	case ColorAuto:
+		return "auto"
+	case ColorAlways:
+		return "always"
+	case ColorNever:
+		return "never"
+	}
+	return "unknown"
+}
+
+// Enabled reports whether color should be used when writing to f.
+//
+// In ColorAuto mode the following conventions are honoured, in order:
+//
+//	NO_COLOR (non-empty)        disables color (https://no-color.org)
+//	CLICOLOR_FORCE (not "0")    enables color, even when f is not a terminal
+//	CLICOLOR=0                  disables color
+//	TERM=dumb                   disables color
+//
+// otherwise color is enabled if f is a terminal.
+func (m Color) Enabled(f *os.File) bool {
+	switch m {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if v := os.Getenv("CLICOLOR_FORCE"); v != "" && v != "0" {
+		return true
+	}
+	if os.Getenv("CLICOLOR") == "0" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(f)
+}
+
+// isTerminal reports whether f is a character device, i.e. a terminal.
+func isTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+type colorModeValue Color
+
+func newColorModeValue(x Color, p *Color) *colorModeValue {
+	*p = x
+	return (*colorModeValue)(p)
+}
+
+func (v *colorModeValue) Set(x string) error {
+	switch x {
+	case "auto":
+		*v = colorModeValue(ColorAuto)
+	case "always":
+		*v = colorModeValue(ColorAlways)
+	case "never":
+		*v = colorModeValue(ColorNever)
+	default:
+		return fmt.Errorf("%q is not one of auto, always, never", x)
+	}
+	return nil
+}
+
+func (v *colorModeValue) String() string {
+	return Color(*v).String()
+}
+
+func (v *colorModeValue) typeName() string { return "color" }
+
+func (v *colorModeValue) Constraint() string { return "one of auto, always, never" }
+func (v *colorModeValue) Example() string    { return "auto" }
+
+// ColorMode defines a Color variable with specified name and usage string, which
+// accepts the values auto, always and never.  Use Color.Enabled to decide whether
+// to write color output, which in auto mode honours the NO_COLOR and CLICOLOR conventions.
+// The return value is the address of a Color variable that stores the value of the variable.
+func (v *VarSet) ColorMode(name, usage string, opts ...Option) *Color {
+	p := new(Color)
+	v.Var(newColorModeValue(ColorAuto, p), name, usage, opts...)
+	return p
+}
+
+// ColorMode defines a Color variable with specified name and usage string, which
+// accepts the values auto, always and never (see VarSet.ColorMode).
+// The return value is the address of a Color variable that stores the value of the variable.
+func ColorMode(name, usage string, opts ...Option) *Color {
+	return CmdVar.ColorMode(name, usage, opts...)
+}
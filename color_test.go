@@ -0,0 +1,75 @@
+package env_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestColorMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		out     env.Color
+		wantErr bool
+	}{
+		// Valid
+		{"auto", env.ColorAuto, false},
+		{"always", env.ColorAlways, false},
+		{"never", env.ColorNever, false},
+
+		// Invalid
+		{"", env.ColorAuto, true},
+		{"yes", env.ColorAuto, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			c := vs.ColorMode("COLOR", "color test")
+
+			if err := vs.Parse(testGetter{"COLOR": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if *c != tt.out {
+				t.Errorf(" = %v, expected %v", *c, tt.out)
+			}
+		})
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "ColorEnabled")
+	if err != nil {
+		t.Fatalf("could not create temporary file: %v", err)
+	}
+	defer tmpFile.Close()
+	defer os.Remove(tmpFile.Name())
+
+	tests := []struct {
+		name string
+		mode env.Color
+		env  map[string]string
+		want bool
+	}{
+		{"always", env.ColorAlways, map[string]string{"NO_COLOR": "1"}, true},
+		{"never", env.ColorNever, map[string]string{"CLICOLOR_FORCE": "1"}, false},
+		{"auto not terminal", env.ColorAuto, nil, false},
+		{"auto force", env.ColorAuto, map[string]string{"CLICOLOR_FORCE": "1"}, true},
+		{"auto force zero", env.ColorAuto, map[string]string{"CLICOLOR_FORCE": "0"}, false},
+		{"auto no color", env.ColorAuto, map[string]string{"NO_COLOR": "1", "CLICOLOR_FORCE": "1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, k := range []string{"NO_COLOR", "CLICOLOR_FORCE", "CLICOLOR"} {
+				t.Setenv(k, tt.env[k])
+			}
+
+			if got := tt.mode.Enabled(tmpFile); got != tt.want {
+				t.Errorf("%v.Enabled() = %v, expected %v", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+// Package azurekv provides an env.Getter which retrieves secrets from Azure Key
+// Vault (https://azure.microsoft.com/products/key-vault).
+package azurekv
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"code.sajari.com/env/internal/remote"
+)
+
+// DefaultIdentityEndpoint is the Azure Instance Metadata Service token endpoint used
+// for managed identity authentication if Getter.IdentityEndpoint is empty.
+const DefaultIdentityEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// apiVersion is the Key Vault REST API version.
+const apiVersion = "7.4"
+
+// Getter retrieves secrets from a Key Vault.  Each secret is downloaded on first
+// use, and later lookups are served from memory.
+//
+// Variables are mapped to secret names by Names, or if a variable is not in Names,
+// by lower-casing it and replacing underscores with dashes (Key Vault secret names
+// may only contain letters, digits and dashes): DB_PASSWORD is the secret
+// db-password.
+//
+// The Getter authenticates with Token, or if Token is empty, with the managed
+// identity of the host (i.e. an AKS node or VM), using the identity with ClientID if
+// the host has more than one.
+type Getter struct {
+	VaultURI string            // vault URI (i.e. "https://myvault.vault.azure.net")
+	Names    map[string]string // variable name to secret name, optional
+
+	Token            string // bearer token for https://vault.azure.net, optional
+	ClientID         string // client ID of a user-assigned managed identity, optional
+	IdentityEndpoint string // managed identity token endpoint, DefaultIdentityEndpoint if empty
+
+	Client *http.Client // HTTP client, http.DefaultClient if nil
+
+	mu           sync.Mutex
+	secrets      map[string]secret
+	token        string
+	tokenExpires time.Time
+}
+
+type secret struct {
+	value string
+	ok    bool
+}
+
+// New returns a Getter which retrieves secrets from the vault at uri using the
+// managed identity of the host.
+func New(uri string) *Getter {
+	return &Getter{VaultURI: uri}
+}
+
+// Get implements env.Getter.  Errors retrieving secrets are reported as unset
+// variables: use GetContext (or env.ParseContext) to see them.
+func (g *Getter) Get(name string) (string, bool) {
+	z, ok, _ := g.GetContext(context.Background(), name)
+	return z, ok
+}
+
+// GetContext implements env.ContextGetter.
+func (g *Getter) GetContext(ctx context.Context, name string) (string, bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if s, ok := g.secrets[name]; ok {
+		return s.value, s.ok, nil
+	}
+
+	s, err := g.load(ctx, g.secretName(name))
+	if err != nil {
+		return "", false, err
+	}
+	if g.secrets == nil {
+		g.secrets = make(map[string]secret)
+	}
+	g.secrets[name] = s
+	return s.value, s.ok, nil
+}
+
+// Reset discards downloaded secrets, so that they are downloaded again on next use.
+func (g *Getter) Reset() {
+	g.mu.Lock()
+	g.secrets = nil
+	g.mu.Unlock()
+}
+
+func (g *Getter) secretName(name string) string {
+	if s, ok := g.Names[name]; ok {
+		return s
+	}
+	return strings.Replace(strings.ToLower(name), "_", "-", -1)
+}
+
+// accessToken returns the bearer token used to read secrets, requesting one for the
+// managed identity if needed.
+func (g *Getter) accessToken(ctx context.Context) (string, error) {
+	if g.Token != "" {
+		return g.Token, nil
+	}
+	if g.token != "" && time.Now().Before(g.tokenExpires) {
+		return g.token, nil
+	}
+
+	endpoint := g.IdentityEndpoint
+	if endpoint == "" {
+		endpoint = DefaultIdentityEndpoint
+	}
+	q := url.Values{
+		"api-version": {"2018-02-01"},
+		"resource":    {"https://vault.azure.net"},
+	}
+	if g.ClientID != "" {
+		q.Set("client_id", g.ClientID)
+	}
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   string `json:"expires_in"` // seconds, encoded as a string
+	}
+	if err := remote.GetJSON(ctx, g.Client, endpoint+"?"+q.Encode(), http.Header{
+		"Metadata": {"true"},
+	}, &resp); err != nil {
+		return "", err
+	}
+	if resp.AccessToken == "" {
+		return "", errors.New("azurekv: managed identity endpoint returned no token")
+	}
+
+	g.token = resp.AccessToken
+	g.tokenExpires = time.Now().Add(time.Minute) // refresh at least every minute if the expiry is unknown
+	if n, err := strconv.Atoi(resp.ExpiresIn); err == nil && n > 0 {
+		g.tokenExpires = time.Now().Add(time.Duration(n) * time.Second)
+	}
+	return g.token, nil
+}
+
+func (g *Getter) load(ctx context.Context, name string) (secret, error) {
+	token, err := g.accessToken(ctx)
+	if err != nil {
+		return secret{}, err
+	}
+
+	var resp struct {
+		Value string `json:"value"`
+	}
+	err = remote.GetJSON(ctx, g.Client, strings.TrimSuffix(g.VaultURI, "/")+"/secrets/"+url.PathEscape(name)+"?api-version="+apiVersion, http.Header{
+		"Authorization": {"Bearer " + token},
+	}, &resp)
+	if remote.IsNotFound(err) {
+		return secret{}, nil
+	}
+	if err != nil {
+		return secret{}, err
+	}
+	return secret{value: resp.Value, ok: true}, nil
+}
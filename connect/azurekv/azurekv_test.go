@@ -0,0 +1,71 @@
+package azurekv_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.sajari.com/env/connect/azurekv"
+)
+
+func TestGetter(t *testing.T) {
+	secrets := map[string]string{"db-password": "hunter2", "legacy-key": "abc"}
+	tokens, reads := 0, 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/identity" {
+			if r.Header.Get("Metadata") != "true" || r.URL.Query().Get("resource") != "https://vault.azure.net" || r.URL.Query().Get("client_id") != "id" {
+				http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+				return
+			}
+			tokens++
+			fmt.Fprint(w, `{"access_token": "msi-token", "expires_in": "3599", "token_type": "Bearer"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer msi-token" {
+			http.Error(w, `{"error":{"code":"Unauthorized"}}`, http.StatusUnauthorized)
+			return
+		}
+		reads++
+		z, ok := secrets[r.URL.Path[len("/secrets/"):]]
+		if !ok || r.URL.Query().Get("api-version") == "" {
+			http.Error(w, `{"error":{"code":"SecretNotFound"}}`, http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"value": %q, "id": "https://myvault.vault.azure.net/secrets/x/1"}`, z)
+	}))
+	defer s.Close()
+
+	g := &azurekv.Getter{
+		VaultURI:         s.URL,
+		Names:            map[string]string{"API_KEY": "legacy-key"},
+		ClientID:         "id",
+		IdentityEndpoint: s.URL + "/identity",
+	}
+	tests := []struct {
+		name string
+		out  string
+		ok   bool
+	}{
+		{"DB_PASSWORD", "hunter2", true},
+		{"API_KEY", "abc", true},
+		{"MISSING", "", false},
+		{"DB_PASSWORD", "hunter2", true},
+		{"MISSING", "", false},
+	}
+	for _, tt := range tests {
+		z, ok, err := g.GetContext(context.Background(), tt.name)
+		if z != tt.out || ok != tt.ok || err != nil {
+			t.Errorf("g.GetContext(%q) = (%q, %v, %v), expected (%q, %v, nil)", tt.name, z, ok, err, tt.out, tt.ok)
+		}
+	}
+	if tokens != 1 || reads != 3 {
+		t.Errorf("got %d token requests and %d reads, expected 1 and 3", tokens, reads)
+	}
+
+	g = &azurekv.Getter{VaultURI: s.URL, Token: "wrong"}
+	if _, _, err := g.GetContext(context.Background(), "DB_PASSWORD"); err == nil {
+		t.Errorf("expected error with invalid token")
+	}
+}
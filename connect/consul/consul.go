@@ -0,0 +1,106 @@
+// Package consul provides an env.Getter which retrieves configuration from the
+// Consul KV store (https://www.consul.io).
+package consul
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"code.sajari.com/env/internal/remote"
+)
+
+// DefaultAddress is the Consul agent used if Getter.Address is empty.
+const DefaultAddress = "http://127.0.0.1:8500"
+
+// Getter retrieves the keys under a prefix from Consul KV.  All keys are downloaded on
+// first use, and later lookups are served from memory.
+//
+// Keys are named by their path relative to Prefix, converted in the same way as
+// VarSet names: with Prefix "config/myapp", the key config/myapp/db/host is the
+// variable DB_HOST.
+type Getter struct {
+	Prefix     string // key prefix, usually one per service (i.e. "config/myapp")
+	Datacenter string // datacenter to query, the agent's datacenter if empty
+
+	Address string       // agent URL, DefaultAddress if empty
+	Token   string       // ACL token, optional
+	Client  *http.Client // HTTP client, http.DefaultClient if nil
+
+	secrets remote.Snapshot
+}
+
+// New returns a Getter which retrieves the keys under prefix, using the agent and ACL
+// token in the standard environment variables CONSUL_HTTP_ADDR and CONSUL_HTTP_TOKEN.
+func New(prefix string) *Getter {
+	addr := os.Getenv("CONSUL_HTTP_ADDR")
+	if addr != "" && !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	return &Getter{
+		Prefix:  prefix,
+		Address: addr,
+		Token:   os.Getenv("CONSUL_HTTP_TOKEN"),
+	}
+}
+
+// Get implements env.Getter.  Errors retrieving keys are reported as unset
+// variables: use GetContext (or env.ParseContext) to see them.
+func (g *Getter) Get(name string) (string, bool) {
+	z, ok, _ := g.GetContext(context.Background(), name)
+	return z, ok
+}
+
+// GetContext implements env.ContextGetter.
+func (g *Getter) GetContext(ctx context.Context, name string) (string, bool, error) {
+	return g.secrets.Get(ctx, name, g.load)
+}
+
+// Reset discards downloaded keys, so that they are downloaded again on next use.
+func (g *Getter) Reset() {
+	g.secrets.Reset()
+}
+
+func (g *Getter) load(ctx context.Context) (map[string]string, error) {
+	addr := g.Address
+	if addr == "" {
+		addr = DefaultAddress
+	}
+	prefix := strings.Trim(g.Prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	q := url.Values{"recurse": {"true"}}
+	if g.Datacenter != "" {
+		q.Set("dc", g.Datacenter)
+	}
+	h := http.Header{}
+	if g.Token != "" {
+		h.Set("X-Consul-Token", g.Token)
+	}
+
+	var kvs []struct {
+		Key   string `json:"Key"`
+		Value []byte `json:"Value"` // base64 encoded in JSON, null for folders
+	}
+	err := remote.GetJSON(ctx, g.Client, strings.TrimSuffix(addr, "/")+"/v1/kv/"+prefix+"?"+q.Encode(), h, &kvs)
+	if remote.IsNotFound(err) {
+		// No keys under the prefix.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		if kv.Value == nil || strings.HasSuffix(kv.Key, "/") {
+			continue
+		}
+		values[remote.VarName(strings.TrimPrefix(kv.Key, prefix))] = string(kv.Value)
+	}
+	return values, nil
+}
@@ -0,0 +1,64 @@
+package consul_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.sajari.com/env/connect/consul"
+)
+
+func TestGetter(t *testing.T) {
+	b64 := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Consul-Token") != "acl" {
+			http.Error(w, "ACL not found", http.StatusForbidden)
+			return
+		}
+		if r.URL.Query().Get("recurse") != "true" || r.URL.Query().Get("dc") != "dc2" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv/config/myapp/":
+			fmt.Fprintf(w, `[
+				{"Key": "config/myapp/", "Value": null},
+				{"Key": "config/myapp/db/host", "Value": %q},
+				{"Key": "config/myapp/log-level", "Value": %q}
+			]`, b64("db.internal"), b64("debug"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer s.Close()
+
+	g := &consul.Getter{Prefix: "config/myapp", Datacenter: "dc2", Address: s.URL, Token: "acl"}
+	tests := []struct {
+		name string
+		out  string
+		ok   bool
+	}{
+		{"DB_HOST", "db.internal", true},
+		{"LOG_LEVEL", "debug", true},
+		{"MISSING", "", false},
+	}
+	for _, tt := range tests {
+		if z, ok := g.Get(tt.name); z != tt.out || ok != tt.ok {
+			t.Errorf("g.Get(%q) = (%q, %v), expected (%q, %v)", tt.name, z, ok, tt.out, tt.ok)
+		}
+	}
+
+	g = &consul.Getter{Prefix: "config/empty", Datacenter: "dc2", Address: s.URL, Token: "acl"}
+	if z, ok, err := g.GetContext(context.Background(), "DB_HOST"); ok || err != nil {
+		t.Errorf("g.GetContext() = (%q, %v, %v), expected unset without error", z, ok, err)
+	}
+
+	g = &consul.Getter{Prefix: "config/myapp", Datacenter: "dc2", Address: s.URL, Token: "wrong"}
+	if _, _, err := g.GetContext(context.Background(), "DB_HOST"); err == nil {
+		t.Errorf("expected error with invalid token")
+	}
+}
@@ -0,0 +1,70 @@
+// Package doppler provides an env.Getter which retrieves secrets from Doppler
+// (https://www.doppler.com).
+package doppler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"code.sajari.com/env/internal/remote"
+)
+
+// DefaultBaseURL is the Doppler API used if Getter.BaseURL is empty.
+const DefaultBaseURL = "https://api.doppler.com"
+
+// Getter retrieves secrets from a Doppler config.  All secrets are downloaded on
+// first use, and later lookups are served from memory.
+type Getter struct {
+	Token   string // service token or personal token
+	Project string // project name, optional for service tokens
+	Config  string // config name (i.e. "dev"), optional for service tokens
+
+	BaseURL string       // API URL, DefaultBaseURL if empty
+	Client  *http.Client // HTTP client, http.DefaultClient if nil
+
+	secrets remote.Snapshot
+}
+
+// New returns a Getter which retrieves secrets using a Doppler service token.
+func New(token string) *Getter {
+	return &Getter{Token: token}
+}
+
+// Get implements env.Getter.  Errors retrieving secrets are reported as unset
+// variables: use GetContext (or env.ParseContext) to see them.
+func (g *Getter) Get(name string) (string, bool) {
+	z, ok, _ := g.GetContext(context.Background(), name)
+	return z, ok
+}
+
+// GetContext implements env.ContextGetter.
+func (g *Getter) GetContext(ctx context.Context, name string) (string, bool, error) {
+	return g.secrets.Get(ctx, name, g.load)
+}
+
+// Reset discards downloaded secrets, so that they are downloaded again on next use.
+func (g *Getter) Reset() {
+	g.secrets.Reset()
+}
+
+func (g *Getter) load(ctx context.Context) (map[string]string, error) {
+	base := g.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+
+	q := url.Values{"format": {"json"}}
+	if g.Project != "" {
+		q.Set("project", g.Project)
+	}
+	if g.Config != "" {
+		q.Set("config", g.Config)
+	}
+
+	var secrets map[string]string
+	err := remote.GetJSON(ctx, g.Client, base+"/v3/configs/config/secrets/download?"+q.Encode(), http.Header{
+		"Authorization": {"Bearer " + g.Token},
+	}, &secrets)
+	return secrets, err
+}
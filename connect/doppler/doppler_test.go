@@ -0,0 +1,44 @@
+package doppler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.sajari.com/env/connect/doppler"
+)
+
+func TestGetter(t *testing.T) {
+	requests := 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer dp.st.test" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v3/configs/config/secrets/download" || r.URL.Query().Get("config") != "dev" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"DB_PASSWORD": "hunter2", "API_KEY": "abc"}`)
+	}))
+	defer s.Close()
+
+	g := &doppler.Getter{Token: "dp.st.test", Config: "dev", BaseURL: s.URL}
+	if z, ok := g.Get("DB_PASSWORD"); !ok || z != "hunter2" {
+		t.Errorf("g.Get() = (%q, %v), expected (\"hunter2\", true)", z, ok)
+	}
+	if z, ok := g.Get("MISSING"); ok {
+		t.Errorf("g.Get() = (%q, %v), expected unset", z, ok)
+	}
+	if requests != 1 {
+		t.Errorf("got %d requests, expected 1", requests)
+	}
+
+	g = &doppler.Getter{Token: "wrong", Config: "dev", BaseURL: s.URL}
+	if _, _, err := g.GetContext(context.Background(), "DB_PASSWORD"); err == nil {
+		t.Errorf("expected error with invalid token")
+	}
+}
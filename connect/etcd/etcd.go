@@ -0,0 +1,146 @@
+// Package etcd provides an env.Getter which retrieves configuration from etcd
+// (https://etcd.io) using its v3 JSON gateway.
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"code.sajari.com/env/internal/remote"
+)
+
+// Getter retrieves the keys under a prefix from etcd.  All keys are downloaded on
+// first use, and later lookups are served from memory.
+//
+// Keys are named by their path relative to Prefix, converted in the same way as
+// VarSet names: with Prefix "/config/myapp/", the key /config/myapp/db/host is the
+// variable DB_HOST.
+type Getter struct {
+	Endpoints []string // client URLs (i.e. "https://etcd-0.example.com:2379"), tried in order
+	Prefix    string   // key prefix (i.e. "/config/myapp/")
+
+	Username string // user for etcd authentication, optional
+	Password string // password for Username
+
+	TLS    *tls.Config  // TLS configuration (i.e. client certificates), used if Client is nil
+	Client *http.Client // HTTP client, http.DefaultClient if nil and TLS is nil
+
+	secrets remote.Snapshot
+}
+
+// New returns a Getter which retrieves the keys under prefix from the etcd cluster at
+// endpoints.
+func New(prefix string, endpoints ...string) *Getter {
+	return &Getter{Endpoints: endpoints, Prefix: prefix}
+}
+
+// Get implements env.Getter.  Errors retrieving keys are reported as unset
+// variables: use GetContext (or env.ParseContext) to see them.
+func (g *Getter) Get(name string) (string, bool) {
+	z, ok, _ := g.GetContext(context.Background(), name)
+	return z, ok
+}
+
+// GetContext implements env.ContextGetter.
+func (g *Getter) GetContext(ctx context.Context, name string) (string, bool, error) {
+	return g.secrets.Get(ctx, name, g.load)
+}
+
+// Reset discards downloaded keys, so that they are downloaded again on next use.
+func (g *Getter) Reset() {
+	g.secrets.Reset()
+}
+
+func (g *Getter) client() *http.Client {
+	if g.Client == nil && g.TLS != nil {
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: g.TLS}}
+	}
+	return g.Client
+}
+
+// post sends in to the gateway path on endpoint as JSON, and decodes the response into out.
+func (g *Getter) post(ctx context.Context, client *http.Client, endpoint, path, token string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	h := http.Header{"Content-Type": {"application/json"}}
+	if token != "" {
+		h.Set("Authorization", token)
+	}
+	return remote.DoJSON(ctx, client, http.MethodPost, strings.TrimSuffix(endpoint, "/")+path, h, bytes.NewReader(body), out)
+}
+
+func (g *Getter) load(ctx context.Context) (map[string]string, error) {
+	if len(g.Endpoints) == 0 {
+		return nil, errors.New("etcd: no endpoints")
+	}
+	client := g.client()
+
+	var err error
+	for _, endpoint := range g.Endpoints {
+		var values map[string]string
+		if values, err = g.loadFrom(ctx, client, endpoint); err == nil {
+			return values, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, err
+}
+
+func (g *Getter) loadFrom(ctx context.Context, client *http.Client, endpoint string) (map[string]string, error) {
+	token := ""
+	if g.Username != "" {
+		var resp struct {
+			Token string `json:"token"`
+		}
+		if err := g.post(ctx, client, endpoint, "/v3/auth/authenticate", "", map[string]string{
+			"name":     g.Username,
+			"password": g.Password,
+		}, &resp); err != nil {
+			return nil, err
+		}
+		token = resp.Token
+	}
+
+	var resp struct {
+		KVs []struct {
+			Key   []byte `json:"key"`   // base64 encoded in JSON
+			Value []byte `json:"value"` // base64 encoded in JSON
+		} `json:"kvs"`
+	}
+	if err := g.post(ctx, client, endpoint, "/v3/kv/range", token, map[string]string{
+		"key":       base64.StdEncoding.EncodeToString([]byte(g.Prefix)),
+		"range_end": base64.StdEncoding.EncodeToString(prefixEnd([]byte(g.Prefix))),
+	}, &resp); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(resp.KVs))
+	for _, kv := range resp.KVs {
+		key := strings.TrimPrefix(string(kv.Key), g.Prefix)
+		values[remote.VarName(strings.TrimPrefix(key, "/"))] = string(kv.Value)
+	}
+	return values, nil
+}
+
+// prefixEnd returns the end of the key range for prefix: the smallest key greater
+// than every key beginning with prefix, or "\x00" (meaning all keys) if there is none.
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte(nil), prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return []byte{0}
+}
@@ -0,0 +1,71 @@
+package etcd_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.sajari.com/env/connect/etcd"
+)
+
+func TestGetter(t *testing.T) {
+	b64 := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, `{"error":"bad request"}`, http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/v3/auth/authenticate":
+			if in["name"] != "app" || in["password"] != "pw" {
+				http.Error(w, `{"error":"authentication failed"}`, http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"token": "tok"}`)
+		case "/v3/kv/range":
+			if r.Header.Get("Authorization") != "tok" {
+				http.Error(w, `{"error":"user name is empty"}`, http.StatusUnauthorized)
+				return
+			}
+			if in["key"] != b64("/config/myapp/") || in["range_end"] != b64("/config/myapp0") {
+				fmt.Fprint(w, `{"kvs": []}`)
+				return
+			}
+			fmt.Fprintf(w, `{"kvs": [{"key": %q, "value": %q}, {"key": %q, "value": %q}], "count": "2"}`,
+				b64("/config/myapp/db/host"), b64("db.internal"),
+				b64("/config/myapp/log-level"), b64("debug"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer s.Close()
+
+	g := etcd.New("/config/myapp/", "http://127.0.0.1:1", s.URL)
+	g.Username, g.Password = "app", "pw"
+	tests := []struct {
+		name string
+		out  string
+		ok   bool
+	}{
+		{"DB_HOST", "db.internal", true},
+		{"LOG_LEVEL", "debug", true},
+		{"MISSING", "", false},
+	}
+	for _, tt := range tests {
+		if z, ok := g.Get(tt.name); z != tt.out || ok != tt.ok {
+			t.Errorf("g.Get(%q) = (%q, %v), expected (%q, %v)", tt.name, z, ok, tt.out, tt.ok)
+		}
+	}
+
+	g = etcd.New("/config/myapp/", s.URL)
+	g.Username, g.Password = "app", "wrong"
+	if _, _, err := g.GetContext(context.Background(), "DB_HOST"); err == nil {
+		t.Errorf("expected error with invalid password")
+	}
+}
@@ -0,0 +1,156 @@
+// Package httpconfig provides an env.Getter which retrieves configuration from a
+// JSON or .env document served over HTTP(S), i.e. by an internal config service.
+package httpconfig
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"code.sajari.com/env"
+)
+
+// DefaultTimeout is the time allowed for each request if Getter.Timeout is zero.
+const DefaultTimeout = 10 * time.Second
+
+// Formats of the document, set by Getter.Format.
+const (
+	FormatAuto   = ""       // detect from the Content-Type of the response
+	FormatJSON   = "json"   // see env.JSON
+	FormatDotenv = "dotenv" // see env.Dotenv
+)
+
+// Getter retrieves configuration from a document at a URL.  The document is
+// downloaded on first use, and later lookups are served from memory.  After Reset,
+// the document is requested again with the ETag of the last response, so an unchanged
+// document is not downloaded again.
+//
+// With FormatAuto, responses with a JSON media type (application/json or
+// application/*+json) are parsed as JSON, and others as .env.
+type Getter struct {
+	URL     string        // document URL
+	Header  http.Header   // request headers (i.e. Authorization), optional
+	Format  string        // document format, FormatAuto if empty
+	Timeout time.Duration // time allowed for each request, DefaultTimeout if zero
+
+	Client *http.Client // HTTP client, http.DefaultClient if nil
+
+	mu     sync.Mutex
+	values env.MapGetter
+	etag   string
+	stale  bool
+}
+
+// New returns a Getter which retrieves configuration from the document at url,
+// detecting its format from the response.
+func New(url string) *Getter {
+	return &Getter{URL: url}
+}
+
+// Get implements env.Getter.  Errors retrieving the document are reported as unset
+// variables: use GetContext (or env.ParseContext) to see them.
+func (g *Getter) Get(name string) (string, bool) {
+	z, ok, _ := g.GetContext(context.Background(), name)
+	return z, ok
+}
+
+// GetContext implements env.ContextGetter.
+func (g *Getter) GetContext(ctx context.Context, name string) (string, bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.values == nil || g.stale {
+		if err := g.load(ctx); err != nil {
+			return "", false, err
+		}
+	}
+	z, ok := g.values[name]
+	return z, ok, nil
+}
+
+// Reset marks the document as stale, so that it is requested again on next use.
+func (g *Getter) Reset() {
+	g.mu.Lock()
+	g.stale = true
+	g.mu.Unlock()
+}
+
+func (g *Getter) load(ctx context.Context) error {
+	timeout := g.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client := g.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequest(http.MethodGet, g.URL, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	for k, vs := range g.Header {
+		req.Header[k] = vs
+	}
+	if g.values != nil && g.etag != "" {
+		req.Header.Set("If-None-Match", g.etag)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && g.values != nil {
+		g.stale = false
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("GET %v: %v: %v", req.URL.Redacted(), resp.Status, strings.TrimSpace(string(b)))
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	values, err := g.parse(resp.Header.Get("Content-Type"), b)
+	if err != nil {
+		return fmt.Errorf("GET %v: %v", req.URL.Redacted(), err)
+	}
+
+	g.values = values
+	g.etag = resp.Header.Get("ETag")
+	g.stale = false
+	return nil
+}
+
+func (g *Getter) parse(contentType string, b []byte) (env.MapGetter, error) {
+	format := g.Format
+	if format == FormatAuto {
+		format = FormatDotenv
+		if t, _, err := mime.ParseMediaType(contentType); err == nil && (t == "application/json" || strings.HasSuffix(t, "+json")) {
+			format = FormatJSON
+		}
+	}
+
+	switch format {
+	case FormatJSON:
+		return env.JSON(bytes.NewReader(b))
+	case FormatDotenv:
+		return env.Dotenv(bytes.NewReader(b))
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
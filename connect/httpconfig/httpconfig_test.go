@@ -0,0 +1,71 @@
+package httpconfig_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"code.sajari.com/env/connect/httpconfig"
+)
+
+func TestGetter(t *testing.T) {
+	downloads, notModified := 0, 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/config.json":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			downloads++
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Header().Set("ETag", `"v1"`)
+			fmt.Fprint(w, `{"db": {"host": "db.internal"}, "workers": 4}`)
+		case "/config.env":
+			fmt.Fprint(w, "DB_HOST=db.internal\nWORKERS=4\n")
+		case "/slow":
+			time.Sleep(100 * time.Millisecond)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer s.Close()
+
+	header := http.Header{"Authorization": {"Bearer tok"}}
+	for _, path := range []string{"/config.json", "/config.env"} {
+		g := &httpconfig.Getter{URL: s.URL + path, Header: header}
+		for i := 0; i < 2; i++ {
+			if z, ok := g.Get("DB_HOST"); !ok || z != "db.internal" {
+				t.Errorf("%v: g.Get() = (%q, %v), expected (\"db.internal\", true)", path, z, ok)
+			}
+			if z, ok := g.Get("WORKERS"); !ok || z != "4" {
+				t.Errorf("%v: g.Get() = (%q, %v), expected (\"4\", true)", path, z, ok)
+			}
+			g.Reset()
+		}
+	}
+	if downloads != 1 || notModified != 1 {
+		t.Errorf("got %d downloads and %d not modified responses, expected 1 and 1", downloads, notModified)
+	}
+
+	g := &httpconfig.Getter{URL: s.URL + "/config.json"}
+	if _, _, err := g.GetContext(context.Background(), "DB_HOST"); err == nil {
+		t.Errorf("expected error without authorization")
+	}
+	g = &httpconfig.Getter{URL: s.URL + "/slow", Header: header, Timeout: 10 * time.Millisecond}
+	if _, _, err := g.GetContext(context.Background(), "DB_HOST"); err == nil {
+		t.Errorf("expected error after timeout")
+	}
+	g = &httpconfig.Getter{URL: s.URL + "/config.env", Header: header, Format: "yaml"}
+	if _, _, err := g.GetContext(context.Background(), "DB_HOST"); err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+}
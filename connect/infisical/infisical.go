@@ -0,0 +1,86 @@
+// Package infisical provides an env.Getter which retrieves secrets from Infisical
+// (https://infisical.com).
+package infisical
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"code.sajari.com/env/internal/remote"
+)
+
+// DefaultBaseURL is the Infisical API used if Getter.BaseURL is empty.
+const DefaultBaseURL = "https://app.infisical.com"
+
+// Getter retrieves secrets from an Infisical project environment.  All secrets in
+// the path are downloaded on first use, and later lookups are served from memory.
+type Getter struct {
+	Token       string // access token
+	WorkspaceID string // project ID
+	Environment string // environment slug (i.e. "dev")
+	Path        string // secret path, "/" if empty
+
+	BaseURL string       // API URL, DefaultBaseURL if empty
+	Client  *http.Client // HTTP client, http.DefaultClient if nil
+
+	secrets remote.Snapshot
+}
+
+// New returns a Getter which retrieves secrets from the root path of an Infisical
+// project environment.
+func New(token, workspaceID, environment string) *Getter {
+	return &Getter{Token: token, WorkspaceID: workspaceID, Environment: environment}
+}
+
+// Get implements env.Getter.  Errors retrieving secrets are reported as unset
+// variables: use GetContext (or env.ParseContext) to see them.
+func (g *Getter) Get(name string) (string, bool) {
+	z, ok, _ := g.GetContext(context.Background(), name)
+	return z, ok
+}
+
+// GetContext implements env.ContextGetter.
+func (g *Getter) GetContext(ctx context.Context, name string) (string, bool, error) {
+	return g.secrets.Get(ctx, name, g.load)
+}
+
+// Reset discards downloaded secrets, so that they are downloaded again on next use.
+func (g *Getter) Reset() {
+	g.secrets.Reset()
+}
+
+func (g *Getter) load(ctx context.Context) (map[string]string, error) {
+	base := g.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	path := g.Path
+	if path == "" {
+		path = "/"
+	}
+
+	q := url.Values{
+		"workspaceId": {g.WorkspaceID},
+		"environment": {g.Environment},
+		"secretPath":  {path},
+	}
+
+	var resp struct {
+		Secrets []struct {
+			Key   string `json:"secretKey"`
+			Value string `json:"secretValue"`
+		} `json:"secrets"`
+	}
+	if err := remote.GetJSON(ctx, g.Client, base+"/api/v3/secrets/raw?"+q.Encode(), http.Header{
+		"Authorization": {"Bearer " + g.Token},
+	}, &resp); err != nil {
+		return nil, err
+	}
+
+	secrets := make(map[string]string, len(resp.Secrets))
+	for _, s := range resp.Secrets {
+		secrets[s.Key] = s.Value
+	}
+	return secrets, nil
+}
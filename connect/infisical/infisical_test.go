@@ -0,0 +1,42 @@
+package infisical_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.sajari.com/env/connect/infisical"
+)
+
+func TestGetter(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer st.test" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		q := r.URL.Query()
+		if r.URL.Path != "/api/v3/secrets/raw" || q.Get("workspaceId") != "ws1" || q.Get("environment") != "dev" || q.Get("secretPath") != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"secrets": [{"secretKey": "DB_PASSWORD", "secretValue": "hunter2"}]}`)
+	}))
+	defer s.Close()
+
+	g := infisical.New("st.test", "ws1", "dev")
+	g.BaseURL = s.URL
+	if z, ok := g.Get("DB_PASSWORD"); !ok || z != "hunter2" {
+		t.Errorf("g.Get() = (%q, %v), expected (\"hunter2\", true)", z, ok)
+	}
+	if z, ok := g.Get("MISSING"); ok {
+		t.Errorf("g.Get() = (%q, %v), expected unset", z, ok)
+	}
+
+	g = infisical.New("st.test", "ws2", "dev")
+	g.BaseURL = s.URL
+	if _, _, err := g.GetContext(context.Background(), "DB_PASSWORD"); err == nil {
+		t.Errorf("expected error for unknown workspace")
+	}
+}
@@ -0,0 +1,72 @@
+// Package onepassword provides an env.Getter which retrieves secrets from an item
+// in a 1Password vault using 1Password Connect
+// (https://developer.1password.com/docs/connect).
+package onepassword
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.sajari.com/env/internal/remote"
+)
+
+// Getter retrieves secrets from the fields of a 1Password item, using field labels
+// as variable names.  The item is downloaded on first use, and later lookups are
+// served from memory.
+type Getter struct {
+	Host  string // URL of the Connect server, i.e. "http://localhost:8080"
+	Token string // Connect access token
+	Vault string // vault ID
+	Item  string // item ID
+
+	Client *http.Client // HTTP client, http.DefaultClient if nil
+
+	fields remote.Snapshot
+}
+
+// New returns a Getter which retrieves the fields of an item from a Connect server.
+func New(host, token, vault, item string) *Getter {
+	return &Getter{Host: host, Token: token, Vault: vault, Item: item}
+}
+
+// Get implements env.Getter.  Errors retrieving the item are reported as unset
+// variables: use GetContext (or env.ParseContext) to see them.
+func (g *Getter) Get(name string) (string, bool) {
+	z, ok, _ := g.GetContext(context.Background(), name)
+	return z, ok
+}
+
+// GetContext implements env.ContextGetter.
+func (g *Getter) GetContext(ctx context.Context, name string) (string, bool, error) {
+	return g.fields.Get(ctx, name, g.load)
+}
+
+// Reset discards the downloaded item, so that it is downloaded again on next use.
+func (g *Getter) Reset() {
+	g.fields.Reset()
+}
+
+func (g *Getter) load(ctx context.Context) (map[string]string, error) {
+	var item struct {
+		Fields []struct {
+			Label string `json:"label"`
+			Value string `json:"value"`
+		} `json:"fields"`
+	}
+	u := strings.TrimSuffix(g.Host, "/") + "/v1/vaults/" + url.PathEscape(g.Vault) + "/items/" + url.PathEscape(g.Item)
+	if err := remote.GetJSON(ctx, g.Client, u, http.Header{
+		"Authorization": {"Bearer " + g.Token},
+	}, &item); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]string, len(item.Fields))
+	for _, f := range item.Fields {
+		if f.Label != "" {
+			fields[f.Label] = f.Value
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,39 @@
+package onepassword_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.sajari.com/env/connect/onepassword"
+)
+
+func TestGetter(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer op.test" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v1/vaults/v1/items/i1" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"id": "i1", "fields": [{"label": "DB_PASSWORD", "value": "hunter2"}, {"label": "", "value": "x"}]}`)
+	}))
+	defer s.Close()
+
+	g := onepassword.New(s.URL, "op.test", "v1", "i1")
+	if z, ok := g.Get("DB_PASSWORD"); !ok || z != "hunter2" {
+		t.Errorf("g.Get() = (%q, %v), expected (\"hunter2\", true)", z, ok)
+	}
+	if z, ok := g.Get(""); ok {
+		t.Errorf("g.Get() = (%q, %v), expected unset", z, ok)
+	}
+
+	g = onepassword.New(s.URL, "op.test", "v1", "i2")
+	if _, _, err := g.GetContext(context.Background(), "DB_PASSWORD"); err == nil {
+		t.Errorf("expected error for unknown item")
+	}
+}
@@ -0,0 +1,25 @@
+package ssm
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// TestSign checks the example from the AWS Signature Version 4 documentation.
+func TestSign(t *testing.T) {
+	u, err := url.Parse("https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := http.Header{"Content-Type": {"application/x-www-form-urlencoded; charset=utf-8"}}
+	c := credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+
+	sign(http.MethodGet, u, h, nil, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC), c, "us-east-1", "iam")
+
+	expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if got := h.Get("Authorization"); got != expected {
+		t.Errorf("got Authorization:\n%v\nexpected:\n%v", got, expected)
+	}
+}
@@ -0,0 +1,179 @@
+// Package ssm provides an env.Getter which retrieves parameters from AWS Systems
+// Manager Parameter Store (https://aws.amazon.com/systems-manager/).
+package ssm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"code.sajari.com/env/internal/remote"
+)
+
+// maxNames is the number of names accepted by each GetParameters call.
+const maxNames = 10
+
+// Getter retrieves parameters from Parameter Store.  All parameters are downloaded on
+// first use, and later lookups are served from memory.  SecureString parameters are
+// decrypted.
+//
+// Parameters beneath Path are named by their path relative to it, converted in the
+// same way as VarSet names: with Path "/myapp/prod", the parameter
+// /myapp/prod/db/password is the variable DB_PASSWORD.  Parameters in Names are
+// retrieved in batches using GetParameters.
+type Getter struct {
+	Path  string            // parameter path prefix (i.e. "/myapp/prod"), optional
+	Names map[string]string // variable name to parameter name, optional
+
+	Region          string // AWS region (i.e. "us-east-1")
+	AccessKeyID     string // AWS access key ID
+	SecretAccessKey string // AWS secret access key
+	SessionToken    string // session token for temporary credentials, optional
+
+	Endpoint string       // API URL, https://ssm.<Region>.amazonaws.com if empty
+	Client   *http.Client // HTTP client, http.DefaultClient if nil
+
+	secrets remote.Snapshot
+}
+
+// New returns a Getter which retrieves all parameters beneath path, using the region
+// and credentials in the standard AWS environment variables (AWS_REGION or
+// AWS_DEFAULT_REGION, AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN).
+func New(path string) *Getter {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	return &Getter{
+		Path:            path,
+		Region:          region,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// Get implements env.Getter.  Errors retrieving parameters are reported as unset
+// variables: use GetContext (or env.ParseContext) to see them.
+func (g *Getter) Get(name string) (string, bool) {
+	z, ok, _ := g.GetContext(context.Background(), name)
+	return z, ok
+}
+
+// GetContext implements env.ContextGetter.
+func (g *Getter) GetContext(ctx context.Context, name string) (string, bool, error) {
+	return g.secrets.Get(ctx, name, g.load)
+}
+
+// Reset discards downloaded parameters, so that they are downloaded again on next use.
+func (g *Getter) Reset() {
+	g.secrets.Reset()
+}
+
+type credentials struct {
+	AccessKeyID, SecretAccessKey, SessionToken string
+}
+
+type parameter struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+}
+
+// call invokes the SSM API action with input in, and decodes the response into out.
+func (g *Getter) call(ctx context.Context, action string, in, out interface{}) error {
+	if g.Region == "" {
+		return errors.New("ssm: no region")
+	}
+	if g.AccessKeyID == "" || g.SecretAccessKey == "" {
+		return errors.New("ssm: no credentials")
+	}
+
+	endpoint := g.Endpoint
+	if endpoint == "" {
+		endpoint = "https://ssm." + g.Region + ".amazonaws.com/"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	h := http.Header{
+		"Content-Type": {"application/x-amz-json-1.1"},
+		"X-Amz-Target": {"AmazonSSM." + action},
+	}
+	sign(http.MethodPost, u, h, body, time.Now(), credentials{g.AccessKeyID, g.SecretAccessKey, g.SessionToken}, g.Region, "ssm")
+	return remote.DoJSON(ctx, g.Client, http.MethodPost, u.String(), h, bytes.NewReader(body), out)
+}
+
+func (g *Getter) load(ctx context.Context) (map[string]string, error) {
+	values := make(map[string]string)
+
+	if g.Path != "" {
+		prefix := strings.TrimSuffix(g.Path, "/") + "/"
+		token := ""
+		for {
+			in := struct {
+				Path           string
+				Recursive      bool
+				WithDecryption bool
+				NextToken      string `json:",omitempty"`
+			}{prefix, true, true, token}
+			var out struct {
+				Parameters []parameter
+				NextToken  string
+			}
+			if err := g.call(ctx, "GetParametersByPath", in, &out); err != nil {
+				return nil, err
+			}
+			for _, p := range out.Parameters {
+				values[remote.VarName(strings.TrimPrefix(p.Name, prefix))] = p.Value
+			}
+			if out.NextToken == "" {
+				break
+			}
+			token = out.NextToken
+		}
+	}
+
+	vars := make(map[string][]string, len(g.Names)) // parameter name to variable names
+	names := make([]string, 0, len(g.Names))
+	for v, p := range g.Names {
+		if _, ok := vars[p]; !ok {
+			names = append(names, p)
+		}
+		vars[p] = append(vars[p], v)
+	}
+	for len(names) > 0 {
+		n := len(names)
+		if n > maxNames {
+			n = maxNames
+		}
+		in := struct {
+			Names          []string
+			WithDecryption bool
+		}{names[:n], true}
+		var out struct {
+			Parameters []parameter
+		}
+		if err := g.call(ctx, "GetParameters", in, &out); err != nil {
+			return nil, err
+		}
+		for _, p := range out.Parameters {
+			for _, v := range vars[p.Name] {
+				values[v] = p.Value
+			}
+		}
+		names = names[n:]
+	}
+	return values, nil
+}
@@ -0,0 +1,104 @@
+package ssm_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"code.sajari.com/env/connect/ssm"
+)
+
+func TestGetter(t *testing.T) {
+	params := map[string]string{
+		"/myapp/prod/db/password": "hunter2",
+		"/myapp/prod/api-key":     "abc",
+		"/shared/region":          "us-east-1",
+	}
+
+	calls := make(map[string]int)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			http.Error(w, `{"__type":"UnrecognizedClientException"}`, http.StatusBadRequest)
+			return
+		}
+		target := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "AmazonSSM.")
+		calls[target]++
+
+		var in struct {
+			Path           string
+			Names          []string
+			WithDecryption bool
+			NextToken      string
+		}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil || !in.WithDecryption {
+			http.Error(w, `{"__type":"ValidationException"}`, http.StatusBadRequest)
+			return
+		}
+
+		switch target {
+		case "GetParametersByPath":
+			// Return one parameter per page.
+			if in.Path != "/myapp/prod/" {
+				fmt.Fprint(w, `{"Parameters": []}`)
+				return
+			}
+			if in.NextToken == "" {
+				fmt.Fprint(w, `{"Parameters": [{"Name": "/myapp/prod/db/password", "Type": "SecureString", "Value": "hunter2"}], "NextToken": "1"}`)
+				return
+			}
+			fmt.Fprint(w, `{"Parameters": [{"Name": "/myapp/prod/api-key", "Type": "String", "Value": "abc"}]}`)
+		case "GetParameters":
+			out := struct {
+				Parameters        []map[string]string
+				InvalidParameters []string
+			}{[]map[string]string{}, []string{}}
+			for _, name := range in.Names {
+				if z, ok := params[name]; ok {
+					out.Parameters = append(out.Parameters, map[string]string{"Name": name, "Value": z})
+					continue
+				}
+				out.InvalidParameters = append(out.InvalidParameters, name)
+			}
+			json.NewEncoder(w).Encode(out)
+		default:
+			http.Error(w, `{"__type":"InvalidAction"}`, http.StatusBadRequest)
+		}
+	}))
+	defer s.Close()
+
+	g := &ssm.Getter{
+		Path:            "/myapp/prod",
+		Names:           map[string]string{"REGION": "/shared/region", "MISSING": "/shared/missing"},
+		Region:          "us-east-1",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		Endpoint:        s.URL,
+	}
+	tests := []struct {
+		name string
+		out  string
+		ok   bool
+	}{
+		{"DB_PASSWORD", "hunter2", true},
+		{"API_KEY", "abc", true},
+		{"REGION", "us-east-1", true},
+		{"MISSING", "", false},
+	}
+	for _, tt := range tests {
+		if z, ok := g.Get(tt.name); z != tt.out || ok != tt.ok {
+			t.Errorf("g.Get(%q) = (%q, %v), expected (%q, %v)", tt.name, z, ok, tt.out, tt.ok)
+		}
+	}
+	if calls["GetParametersByPath"] != 2 || calls["GetParameters"] != 1 {
+		t.Errorf("got calls %v, expected 2 GetParametersByPath and 1 GetParameters", calls)
+	}
+
+	g = &ssm.Getter{Path: "/myapp/prod", Region: "us-east-1", AccessKeyID: "wrong", SecretAccessKey: "secret", Endpoint: s.URL}
+	if _, _, err := g.GetContext(context.Background(), "DB_PASSWORD"); err == nil {
+		t.Errorf("expected error with invalid credentials")
+	}
+}
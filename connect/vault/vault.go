@@ -0,0 +1,194 @@
+// Package vault provides an env.Getter which retrieves secrets from a HashiCorp
+// Vault KV secrets engine (https://www.vaultproject.io).
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"code.sajari.com/env"
+	"code.sajari.com/env/internal/remote"
+)
+
+// Getter retrieves secrets from a Vault KV path.  All keys of the secret are
+// downloaded on first use, and later lookups are served from memory until the lease
+// returned by Vault (or TTL, if Vault does not return one) expires.
+//
+// The Getter authenticates with Token, or if Token is empty, by logging in with
+// RoleID and SecretID using the AppRole auth method.  AppRole tokens are renewed by
+// logging in again when their lease expires.
+type Getter struct {
+	Address   string // Vault address (i.e. "https://vault.example.com:8200")
+	Namespace string // Vault Enterprise namespace, optional
+
+	Token        string // Vault token
+	RoleID       string // AppRole role ID, used if Token is empty
+	SecretID     string // AppRole secret ID
+	AppRoleMount string // AppRole auth mount, "approle" if empty
+
+	Mount     string        // KV secrets engine mount, "secret" if empty
+	Path      string        // secret path within the mount (i.e. "myapp/prod")
+	KVVersion int           // KV secrets engine version: 1, or 2 if zero
+	TTL       time.Duration // how long to cache secrets without a lease, forever if zero
+
+	Client *http.Client // HTTP client, http.DefaultClient if nil
+	Clock  env.Clock    // clock for lease expiry, the system clock if nil
+
+	secrets remote.Snapshot
+
+	// AppRole token and its expiry, only accessed while loading secrets.
+	token        string
+	tokenExpires time.Time
+}
+
+// New returns a Getter which retrieves secrets from the KV version 2 path at address
+// using token.
+func New(address, token, path string) *Getter {
+	return &Getter{Address: address, Token: token, Path: path}
+}
+
+// Get implements env.Getter.  Errors retrieving secrets are reported as unset
+// variables: use GetContext (or env.ParseContext) to see them.
+func (g *Getter) Get(name string) (string, bool) {
+	z, ok, _ := g.GetContext(context.Background(), name)
+	return z, ok
+}
+
+// GetContext implements env.ContextGetter.
+func (g *Getter) GetContext(ctx context.Context, name string) (string, bool, error) {
+	return g.secrets.GetTTL(ctx, name, g.now(), g.load)
+}
+
+// Reset discards downloaded secrets, so that they are downloaded again on next use.
+func (g *Getter) Reset() {
+	g.secrets.Reset()
+}
+
+func (g *Getter) now() time.Time {
+	if g.Clock == nil {
+		return time.Now()
+	}
+	return g.Clock.Now()
+}
+
+func (g *Getter) url(path string) string {
+	return strings.TrimSuffix(g.Address, "/") + "/v1/" + strings.Trim(path, "/")
+}
+
+func (g *Getter) header(token string) http.Header {
+	h := http.Header{}
+	if token != "" {
+		h.Set("X-Vault-Token", token)
+	}
+	if g.Namespace != "" {
+		h.Set("X-Vault-Namespace", g.Namespace)
+	}
+	return h
+}
+
+// login returns the token used to read secrets, logging in with AppRole if needed.
+func (g *Getter) login(ctx context.Context) (string, error) {
+	if g.Token != "" {
+		return g.Token, nil
+	}
+	if g.RoleID == "" {
+		return "", errors.New("vault: no token or AppRole role ID")
+	}
+	if g.token != "" && (g.tokenExpires.IsZero() || g.now().Before(g.tokenExpires)) {
+		return g.token, nil
+	}
+
+	mount := g.AppRoleMount
+	if mount == "" {
+		mount = "approle"
+	}
+	body, err := json.Marshal(map[string]string{"role_id": g.RoleID, "secret_id": g.SecretID})
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	h := g.header("")
+	h.Set("Content-Type", "application/json")
+	if err := remote.DoJSON(ctx, g.Client, http.MethodPost, g.url("auth/"+mount+"/login"), h, bytes.NewReader(body), &resp); err != nil {
+		return "", err
+	}
+	if resp.Auth.ClientToken == "" {
+		return "", errors.New("vault: AppRole login returned no token")
+	}
+
+	g.token = resp.Auth.ClientToken
+	g.tokenExpires = time.Time{}
+	if resp.Auth.LeaseDuration > 0 {
+		g.tokenExpires = g.now().Add(time.Duration(resp.Auth.LeaseDuration) * time.Second)
+	}
+	return g.token, nil
+}
+
+func (g *Getter) load(ctx context.Context) (map[string]string, time.Duration, error) {
+	token, err := g.login(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	mount := g.Mount
+	if mount == "" {
+		mount = "secret"
+	}
+	path := strings.Trim(mount, "/") + "/data/" + strings.Trim(g.Path, "/")
+	if g.KVVersion == 1 {
+		path = strings.Trim(mount, "/") + "/" + strings.Trim(g.Path, "/")
+	}
+
+	var resp struct {
+		Data          json.RawMessage `json:"data"`
+		LeaseDuration int             `json:"lease_duration"`
+	}
+	if err := remote.GetJSON(ctx, g.Client, g.url(path), g.header(token), &resp); err != nil {
+		return nil, 0, err
+	}
+
+	data := resp.Data
+	if g.KVVersion != 1 {
+		var v2 struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(data, &v2); err != nil {
+			return nil, 0, err
+		}
+		data = v2.Data
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, err
+	}
+	secrets := make(map[string]string, len(raw))
+	for k, v := range raw {
+		if s, ok := v.(string); ok {
+			secrets[k] = s
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, 0, err
+		}
+		secrets[k] = string(b)
+	}
+
+	ttl := g.TTL
+	if resp.LeaseDuration > 0 {
+		ttl = time.Duration(resp.LeaseDuration) * time.Second
+	}
+	return secrets, ttl, nil
+}
@@ -0,0 +1,125 @@
+package vault_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"code.sajari.com/env"
+	"code.sajari.com/env/connect/vault"
+)
+
+func TestGetter(t *testing.T) {
+	reads := 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.test" {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/myapp/prod":
+			reads++
+			fmt.Fprint(w, `{"data": {"data": {"DB_PASSWORD": "hunter2", "PORT": 8080}, "metadata": {"version": 3}}, "lease_duration": 0}`)
+		case "/v1/kv/myapp":
+			reads++
+			fmt.Fprint(w, `{"data": {"API_KEY": "abc"}, "lease_duration": 60}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer s.Close()
+
+	g := vault.New(s.URL, "s.test", "myapp/prod")
+	if z, ok := g.Get("DB_PASSWORD"); !ok || z != "hunter2" {
+		t.Errorf("g.Get() = (%q, %v), expected (\"hunter2\", true)", z, ok)
+	}
+	if z, ok := g.Get("PORT"); !ok || z != "8080" {
+		t.Errorf("g.Get() = (%q, %v), expected (\"8080\", true)", z, ok)
+	}
+	if z, ok := g.Get("MISSING"); ok {
+		t.Errorf("g.Get() = (%q, %v), expected unset", z, ok)
+	}
+	if reads != 1 {
+		t.Errorf("got %d reads, expected 1", reads)
+	}
+
+	// KV version 1 with a lease: secrets are read again once it expires.
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	reads = 0
+	g = &vault.Getter{
+		Address:   s.URL,
+		Token:     "s.test",
+		Mount:     "kv",
+		Path:      "myapp",
+		KVVersion: 1,
+		Clock:     env.ClockFunc(func() time.Time { return now }),
+	}
+	for _, d := range []time.Duration{0, 30 * time.Second, 31 * time.Second} {
+		now = now.Add(d)
+		if z, ok := g.Get("API_KEY"); !ok || z != "abc" {
+			t.Errorf("g.Get() = (%q, %v), expected (\"abc\", true)", z, ok)
+		}
+	}
+	if reads != 2 {
+		t.Errorf("got %d reads, expected 2", reads)
+	}
+
+	g = vault.New(s.URL, "wrong", "myapp/prod")
+	if _, _, err := g.GetContext(context.Background(), "DB_PASSWORD"); err == nil {
+		t.Errorf("expected error with invalid token")
+	}
+}
+
+func TestGetterAppRole(t *testing.T) {
+	logins := 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			var body struct {
+				RoleID   string `json:"role_id"`
+				SecretID string `json:"secret_id"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.RoleID != "role" || body.SecretID != "secret" {
+				http.Error(w, `{"errors":["invalid role or secret ID"]}`, http.StatusBadRequest)
+				return
+			}
+			logins++
+			fmt.Fprint(w, `{"auth": {"client_token": "s.approle", "lease_duration": 3600}}`)
+		case "/v1/secret/data/myapp":
+			if r.Header.Get("X-Vault-Token") != "s.approle" || r.Header.Get("X-Vault-Namespace") != "team" {
+				http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+				return
+			}
+			fmt.Fprint(w, `{"data": {"data": {"DB_PASSWORD": "hunter2"}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer s.Close()
+
+	g := &vault.Getter{
+		Address:   s.URL,
+		Namespace: "team",
+		RoleID:    "role",
+		SecretID:  "secret",
+		Path:      "myapp",
+	}
+	for i := 0; i < 2; i++ {
+		if z, ok := g.Get("DB_PASSWORD"); !ok || z != "hunter2" {
+			t.Errorf("g.Get() = (%q, %v), expected (\"hunter2\", true)", z, ok)
+		}
+		g.Reset()
+	}
+	if logins != 1 {
+		t.Errorf("got %d logins, expected 1", logins)
+	}
+
+	g = &vault.Getter{Address: s.URL, RoleID: "role", SecretID: "wrong", Path: "myapp"}
+	if _, _, err := g.GetContext(context.Background(), "DB_PASSWORD"); err == nil {
+		t.Errorf("expected error with invalid secret ID")
+	}
+}
@@ -0,0 +1,69 @@
+package env
+
+import (
+	"context"
+	"fmt"
+)
+
+// ContextGetter is a Getter which can also retrieve variables using a context,
+// for Getters backed by remote stores which need cancellation and deadlines.
+type ContextGetter interface {
+	Getter
+
+	// GetContext retrieves an environment variable.  It returns an error if the
+	// variable could not be retrieved, as distinct from being unset.
+	GetContext(ctx context.Context, name string) (string, bool, error)
+}
+
+// contextGetter adapts a ContextGetter to a Getter using a fixed context.  After the
+// first error (including cancellation of the context) Get reports all variables as
+// unset, and the error is kept in err.
+type contextGetter struct {
+	ctx context.Context
+	g   Getter
+	err error
+}
+
+func (g *contextGetter) Get(x string) (string, bool) {
+	if g.err != nil {
+		return "", false
+	}
+	if err := g.ctx.Err(); err != nil {
+		g.err = err
+		return "", false
+	}
+
+	cg, ok := g.g.(ContextGetter)
+	if !ok {
+		return g.g.Get(x)
+	}
+	z, ok, err := cg.GetContext(g.ctx, x)
+	if err != nil {
+		g.err = fmt.Errorf("could not get env %v: %v", x, err)
+		return "", false
+	}
+	return z, ok
+}
+
+// ParseContext parses variables from the environment provided by the Getter in the
+// same way as Parse.  If the Getter is a ContextGetter then ctx is passed to it.  If
+// ctx is cancelled or the Getter fails then parsing stops and the error is returned,
+// in place of errors for the variables which could not be retrieved.
+func (v *VarSet) ParseContext(ctx context.Context, g Getter, opts ...ParseOption) error {
+	var c parseConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	cg := &contextGetter{ctx: ctx, g: g}
+	err := v.parse(cg, c)
+	if cg.err != nil {
+		err = Errors{cg.err}
+	}
+	v.emitEvents(c, err)
+	return err
+}
+
+// ParseContext parses variables from the process environment (see VarSet.ParseContext).
+func ParseContext(ctx context.Context, opts ...ParseOption) error {
+	return CmdVar.ParseContext(ctx, osLookup{}, opts...)
+}
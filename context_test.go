@@ -0,0 +1,70 @@
+package env_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+// remoteGetter is a ContextGetter which fails for names in errs.
+type remoteGetter struct {
+	testGetter
+	errs map[string]error
+}
+
+func (g remoteGetter) GetContext(ctx context.Context, x string) (string, bool, error) {
+	if err := g.errs[x]; err != nil {
+		return "", false, err
+	}
+	z, ok := g.Get(x)
+	return z, ok, nil
+}
+
+func TestParseContext(t *testing.T) {
+	vs := env.NewVarSet("")
+	name := vs.String("NAME", "context test")
+	vs.String("TOKEN", "context test")
+
+	g := remoteGetter{testGetter: testGetter{"NAME": "a", "TOKEN": "t"}}
+	if err := vs.ParseContext(context.Background(), g); err != nil {
+		t.Errorf("unexpected error from ParseContext: %v", err)
+	}
+	if *name != "a" {
+		t.Errorf("got %q, expected \"a\"", *name)
+	}
+
+	errUnavailable := errors.New("unavailable")
+	g.errs = map[string]error{"TOKEN": errUnavailable}
+	err := vs.ParseContext(context.Background(), g)
+	if es, ok := err.(env.Errors); !ok || len(es) != 1 {
+		t.Errorf("got %v, expected one error from Getter", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = vs.ParseContext(ctx, testGetter{"NAME": "b", "TOKEN": "t"})
+	if es, ok := err.(env.Errors); !ok || len(es) != 1 || es[0] != context.Canceled {
+		t.Errorf("got %v, expected context.Canceled", err)
+	}
+	if *name != "a" {
+		t.Errorf("got %q, expected value unchanged after cancellation", *name)
+	}
+}
+
+func TestParseContextEvents(t *testing.T) {
+	vs := env.NewVarSet("")
+	vs.String("TOKEN", "context test")
+	vs.String("NAME", "context test")
+
+	var events []env.Event
+	onEvent := env.OnEvent(func(e env.Event) { events = append(events, e) })
+	g := remoteGetter{testGetter: testGetter{"NAME": "a"}, errs: map[string]error{"TOKEN": errors.New("unavailable")}}
+	if err := vs.ParseContext(context.Background(), g, onEvent); err == nil {
+		t.Fatalf("expected error from ParseContext")
+	}
+	if len(events) != 1 || events[0].Reason != "check" || events[0].Variable != "" {
+		t.Errorf("got events %+v, expected one event for the Getter failure", events)
+	}
+}
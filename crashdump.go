@@ -0,0 +1,93 @@
+package env
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+)
+
+// crashDump is the content of a file written by WriteCrashDump.
+type crashDump struct {
+	Time time.Time      `json:"time"`
+	PID  int            `json:"pid"`
+	Args []string       `json:"args"`
+	Vars []crashDumpVar `json:"vars"`
+}
+
+type crashDumpVar struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"` // empty if the default was used
+}
+
+// WriteCrashDump writes the current values of the variables in the set, along with
+// the name which supplied each one (see Var.Source), to the file at path as JSON, so
+// that a postmortem has the exact configuration a crashed process was running with.
+// The command line is included, and the values of secret variables are replaced by
+// "xxxxx" wherever they appear, including as flags (see BindFlags).  The file is replaced
+// atomically, so it is safe to call from a panic handler:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			vs.WriteCrashDump("/var/run/myapp/crash.json")
+//			panic(r)
+//		}
+//	}()
+func (v *VarSet) WriteCrashDump(path string) error {
+	d := crashDump{
+		Time: v.now(),
+		PID:  os.Getpid(),
+		Vars: []crashDumpVar{},
+	}
+	secretFlags := make(map[string]bool)
+	v.Visit(func(x *Var) {
+		z := x.Value.String()
+		if x.secret {
+			z = "xxxxx"
+			secretFlags[flagName(v.prefix, x)] = true
+		}
+		d.Vars = append(d.Vars, crashDumpVar{Name: x.Name, Value: z, Source: x.source})
+	})
+
+	d.Args = redactArgs(os.Args, secretFlags)
+
+	b, err := json.MarshalIndent(d, "", "    ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(b, '\n'))
+}
+
+// redactArgs returns a copy of the command line args with the values of the named flags
+// replaced by "xxxxx", whether given as -name=value or -name value.
+func redactArgs(args []string, flags map[string]bool) []string {
+	redacted := append([]string(nil), args...)
+	for i := 1; i < len(redacted); i++ {
+		a := redacted[i]
+		if a == "--" {
+			break
+		}
+		if !strings.HasPrefix(a, "-") {
+			continue
+		}
+		name := strings.TrimPrefix(strings.TrimPrefix(a, "-"), "-")
+		if j := strings.Index(name, "="); j >= 0 {
+			if flags[name[:j]] {
+				redacted[i] = a[:len(a)-len(name)+j+1] + "xxxxx"
+			}
+			continue
+		}
+		if flags[name] && i+1 < len(redacted) {
+			i++
+			redacted[i] = "xxxxx"
+		}
+	}
+	return redacted
+}
+
+// WriteCrashDump writes the current values of the variables in the default set to
+// the file at path (see VarSet.WriteCrashDump).
+func WriteCrashDump(path string) error {
+	return CmdVar.WriteCrashDump(path)
+}
@@ -0,0 +1,68 @@
+package env_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"code.sajari.com/env"
+)
+
+func TestWriteCrashDump(t *testing.T) {
+	dir, err := ioutil.TempDir("", "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "crash.json")
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	vs := env.NewVarSet("")
+	vs.SetClock(env.ClockFunc(func() time.Time { return now }))
+	vs.String("NAME", "name", env.FallbackTo("LEGACY_NAME"))
+	vs.String("TOKEN", "token", env.Secret())
+	vs.Int("WORKERS", "workers", env.Default("4"))
+	if err := vs.Parse(testGetter{"LEGACY_NAME": "svc", "TOKEN": "s3cret"}); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+
+	args := os.Args
+	defer func() { os.Args = args }()
+	os.Args = []string{"svc", "-name=svc", "-token=s3cret", "--token", "s3cret", "-workers", "4", "--", "-token", "x"}
+
+	if err := vs.WriteCrashDump(path); err != nil {
+		t.Fatalf("unexpected error from WriteCrashDump: %v", err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Time time.Time           `json:"time"`
+		PID  int                 `json:"pid"`
+		Args []string            `json:"args"`
+		Vars []map[string]string `json:"vars"`
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("invalid crash dump %q: %v", b, err)
+	}
+	if !got.Time.Equal(now) || got.PID != os.Getpid() {
+		t.Errorf("got time %v and pid %d, expected %v and %d", got.Time, got.PID, now, os.Getpid())
+	}
+	if expected := []string{"svc", "-name=svc", "-token=xxxxx", "--token", "xxxxx", "-workers", "4", "--", "-token", "x"}; !reflect.DeepEqual(got.Args, expected) {
+		t.Errorf("got args %q, expected %q", got.Args, expected)
+	}
+	expected := []map[string]string{
+		{"name": "NAME", "value": "svc", "source": "LEGACY_NAME"},
+		{"name": "TOKEN", "value": "xxxxx", "source": "TOKEN"},
+		{"name": "WORKERS", "value": "4", "source": ""},
+	}
+	if !reflect.DeepEqual(got.Vars, expected) {
+		t.Errorf("got vars %v, expected %v", got.Vars, expected)
+	}
+}
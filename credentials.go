@@ -0,0 +1,63 @@
+package env
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// credentialsGetter is a Getter for the credentials in a systemd credentials
+// directory.
+type credentialsGetter struct {
+	dir string
+}
+
+func (g credentialsGetter) Get(x string) (string, bool) {
+	z, ok, _ := g.GetContext(context.Background(), x)
+	return z, ok
+}
+
+func (g credentialsGetter) GetContext(ctx context.Context, x string) (string, bool, error) {
+	if g.dir == "" {
+		return "", false, nil
+	}
+	if z, ok, err := (dirGetter{path: g.dir}).GetContext(ctx, x); ok || err != nil {
+		return z, ok, err
+	}
+
+	fis, err := ioutil.ReadDir(g.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	for _, fi := range fis {
+		if name := fi.Name(); name[0] != '.' && suffixName(name) == x {
+			z, err := readFileValue(filepath.Join(g.dir, name))
+			if err != nil {
+				return "", false, err
+			}
+			return z, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// SystemdCredentials returns a Getter for the credentials passed to a systemd service
+// with LoadCredential= or SetCredential=, which are files in the directory given by
+// $CREDENTIALS_DIRECTORY.  Each variable is read from the credential of the same name,
+// or from a credential whose name converts to it in the same way as VarSet names:
+//
+//	LoadCredential=db-password:/etc/myapp/db-password   ; DB_PASSWORD
+//
+// A single trailing newline is removed from each value.  If $CREDENTIALS_DIRECTORY is
+// unset, i.e. the process was not started by systemd with credentials, then no
+// variables are found.
+//
+// The returned Getter is also a ContextGetter, which reports errors reading the
+// credentials.
+func SystemdCredentials() Getter {
+	return credentialsGetter{dir: os.Getenv("CREDENTIALS_DIRECTORY")}
+}
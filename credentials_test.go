@@ -0,0 +1,55 @@
+package env_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestSystemdCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"db-password": "s3cret\n",
+		"API_KEY":     "abc",
+		".hidden":     "x",
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0400); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defer os.Unsetenv("CREDENTIALS_DIRECTORY")
+	os.Setenv("CREDENTIALS_DIRECTORY", dir)
+
+	g := env.SystemdCredentials()
+	tests := []struct {
+		name string
+		out  string
+		ok   bool
+	}{
+		{"DB_PASSWORD", "s3cret", true},
+		{"db-password", "s3cret", true},
+		{"API_KEY", "abc", true},
+		{"HIDDEN", "", false},
+		{"MISSING", "", false},
+	}
+	for _, tt := range tests {
+		if z, ok := g.Get(tt.name); z != tt.out || ok != tt.ok {
+			t.Errorf("Get(%q) = (%q, %v), expected (%q, %v)", tt.name, z, ok, tt.out, tt.ok)
+		}
+	}
+
+	os.Unsetenv("CREDENTIALS_DIRECTORY")
+	if z, ok := env.SystemdCredentials().Get("API_KEY"); ok {
+		t.Errorf("Get(%q) = (%q, %v) without CREDENTIALS_DIRECTORY, expected unset", "API_KEY", z, ok)
+	}
+}
@@ -0,0 +1,277 @@
+package env
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron schedule.
+type Schedule struct {
+	expr string
+
+	second, minute, hour, dom, month, dow uint64
+
+	domStar, dowStar bool // day of month/week were unrestricted
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonths = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDays = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// ParseCron parses a cron expression.  Expressions have either 5 fields (minute,
+// hour, day of month, month and day of week) or 6 fields with a leading seconds
+// field.  Fields may contain "*", values, ranges ("1-5"), steps ("*/15", "0-30/5")
+// and lists ("1,15").  Months and days of the week may be given by three letter
+// names ("JAN", "MON").  The descriptors @yearly, @annually, @monthly, @weekly,
+// @daily, @midnight and @hourly are also accepted.
+func ParseCron(expr string) (*Schedule, error) {
+	s := &Schedule{expr: expr}
+
+	x := strings.TrimSpace(expr)
+	if d, ok := cronDescriptors[strings.ToLower(x)]; ok {
+		x = d
+	}
+
+	fields := strings.Fields(x)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("parsing %q: expected 5 or 6 fields, got %d", expr, len(fields))
+	}
+
+	var err error
+	if s.second, _, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("parsing %q: second: %v", expr, err)
+	}
+	if s.minute, _, err = parseCronField(fields[1], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("parsing %q: minute: %v", expr, err)
+	}
+	if s.hour, _, err = parseCronField(fields[2], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("parsing %q: hour: %v", expr, err)
+	}
+	if s.dom, s.domStar, err = parseCronField(fields[3], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("parsing %q: day of month: %v", expr, err)
+	}
+	if s.month, _, err = parseCronField(fields[4], 1, 12, cronMonths); err != nil {
+		return nil, fmt.Errorf("parsing %q: month: %v", expr, err)
+	}
+	if s.dow, s.dowStar, err = parseCronField(fields[5], 0, 7, cronDays); err != nil {
+		return nil, fmt.Errorf("parsing %q: day of week: %v", expr, err)
+	}
+	// Sunday is both 0 and 7.
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	return s, nil
+}
+
+// parseCronField parses a single cron field with values in the range [min, max],
+// returning the set bits and whether the field was unrestricted ("*").
+func parseCronField(field string, min, max int, names map[string]int) (uint64, bool, error) {
+	var bits uint64
+	star := false
+	for _, part := range strings.Split(field, ",") {
+		rangeStep := strings.Split(part, "/")
+		if len(rangeStep) > 2 {
+			return 0, false, fmt.Errorf("invalid step in %q", part)
+		}
+
+		var lo, hi int
+		switch r := rangeStep[0]; {
+		case r == "*" || r == "?":
+			lo, hi = min, max
+			star = len(rangeStep) == 1
+		case strings.Contains(r, "-"):
+			bounds := strings.SplitN(r, "-", 2)
+			var err error
+			if lo, err = parseCronValue(bounds[0], names); err != nil {
+				return 0, false, err
+			}
+			if hi, err = parseCronValue(bounds[1], names); err != nil {
+				return 0, false, err
+			}
+		default:
+			var err error
+			if lo, err = parseCronValue(r, names); err != nil {
+				return 0, false, err
+			}
+			hi = lo
+			if len(rangeStep) == 2 {
+				hi = max
+			}
+		}
+
+		step := 1
+		if len(rangeStep) == 2 {
+			n, err := strconv.Atoi(rangeStep[1])
+			if err != nil || n <= 0 {
+				return 0, false, fmt.Errorf("invalid step in %q", part)
+			}
+			step = n
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, false, fmt.Errorf("%q out of range [%d, %d]", part, min, max)
+		}
+		for i := lo; i <= hi; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, star, nil
+}
+
+func parseCronValue(x string, names map[string]int) (int, error) {
+	if n, ok := names[strings.ToLower(x)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(x)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", x)
+	}
+	return n, nil
+}
+
+// String returns the expression the schedule was parsed from.
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// dayMatches reports whether the day of t matches the schedule.  If both the day
+// of month and day of week are restricted then either may match.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Next returns the first time after t which matches the schedule, in the location
+// of t.  The zero time is returned if no time matches within five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Add(time.Second - time.Duration(t.Nanosecond())*time.Nanosecond)
+	yearLimit := t.Year() + 5
+
+	// Whenever a field is advanced all lower fields are reset.
+	reset := false
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for s.month&(1<<uint(t.Month())) == 0 {
+		if !reset {
+			reset = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 1, 0)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !s.dayMatches(t) {
+		if !reset {
+			reset = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 0, 1)
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for s.hour&(1<<uint(t.Hour())) == 0 {
+		if !reset {
+			reset = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+		}
+		t = t.Add(time.Hour)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+
+	for s.minute&(1<<uint(t.Minute())) == 0 {
+		if !reset {
+			reset = true
+			t = t.Truncate(time.Minute)
+		}
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	for s.second&(1<<uint(t.Second())) == 0 {
+		if !reset {
+			reset = true
+			t = t.Truncate(time.Second)
+		}
+		t = t.Add(time.Second)
+		if t.Second() == 0 {
+			goto wrap
+		}
+	}
+	return t
+}
+
+type cronValue struct {
+	p *Schedule
+}
+
+func (v *cronValue) Set(x string) error {
+	s, err := ParseCron(x)
+	if err != nil {
+		return err
+	}
+	*v.p = *s
+	return nil
+}
+
+func (v *cronValue) String() string {
+	return v.p.String()
+}
+
+func (v *cronValue) typeName() string { return "schedule" }
+
+func (v *cronValue) Constraint() string { return "cron expression" }
+func (v *cronValue) Example() string    { return "*/15 * * * *" }
+
+// Cron defines a Schedule variable with specified name and usage string, validated
+// as a cron expression (see ParseCron).
+// The return value is the address of a Schedule variable that stores the value of the variable.
+func (v *VarSet) Cron(name, usage string, opts ...Option) *Schedule {
+	p := new(Schedule)
+	v.Var(&cronValue{p}, name, usage, opts...)
+	return p
+}
+
+// Cron defines a Schedule variable with specified name and usage string, validated
+// as a cron expression (see ParseCron).
+// The return value is the address of a Schedule variable that stores the value of the variable.
+func Cron(name, usage string, opts ...Option) *Schedule {
+	return CmdVar.Cron(name, usage, opts...)
+}
@@ -0,0 +1,83 @@
+package env_test
+
+import (
+	"testing"
+	"time"
+
+	"code.sajari.com/env"
+)
+
+func TestCron(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		// Valid
+		{"* * * * *", false},
+		{"*/15 * * * *", false},
+		{"0 9-17 * * MON-FRI", false},
+		{"0 0 1,15 * *", false},
+		{"30 0 0 * * *", false},
+		{"0 0 * JAN,JUL 7", false},
+		{"@daily", false},
+		{"@hourly", false},
+
+		// Invalid
+		{"", true},
+		{"* * * *", true},
+		{"* * * * * * *", true},
+		{"60 * * * *", true},
+		{"* 24 * * *", true},
+		{"* * 0 * *", true},
+		{"* * * 13 *", true},
+		{"* * * * 8", true},
+		{"5-1 * * * *", true},
+		{"*/0 * * * *", true},
+		{"a * * * *", true},
+		{"@sometimes", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			vs.Cron("SCHEDULE", "cron test")
+
+			if err := vs.Parse(testGetter{"SCHEDULE": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	from := time.Date(2018, 3, 7, 10, 20, 30, 500, time.UTC) // Wednesday
+
+	tests := []struct {
+		expr string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2018, 3, 7, 10, 21, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2018, 3, 7, 10, 30, 0, 0, time.UTC)},
+		{"* * * * * *", time.Date(2018, 3, 7, 10, 20, 31, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2018, 3, 8, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * MON", time.Date(2018, 3, 12, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * FRI", time.Date(2018, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+		{"@weekly", time.Date(2018, 3, 11, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := env.ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("env.ParseCron(%q) = %v", tt.expr, err)
+			}
+			if next := s.Next(from); !next.Equal(tt.next) {
+				t.Errorf("Next() = %v, expected %v", next, tt.next)
+			}
+		})
+	}
+}
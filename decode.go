@@ -0,0 +1,141 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// decoder transforms the value of a variable before it is set, passing any
+// warnings to warn.
+type decoder func(x string, warn func(string)) (string, error)
+
+// decodeWith returns an Option which applies fn to the value of a variable
+// before it is set.
+func decodeWith(fn func(string) (string, error)) Option {
+	return func(v *Var) {
+		v.decode = append(v.decode, func(x string, _ func(string)) (string, error) {
+			return fn(x)
+		})
+	}
+}
+
+// URLDecode decodes percent-encoded values (i.e. "a%20b") before they are set.
+// Unlike query decoding, "+" is left unchanged.
+func URLDecode() Option {
+	return decodeWith(url.PathUnescape)
+}
+
+// Unescape interprets Go backslash escape sequences in values (i.e. "\n", "\t",
+// "\u00e9") before they are set.
+func Unescape() Option {
+	return decodeWith(unescape)
+}
+
+// unescape interprets the backslash escape sequences in x.
+func unescape(x string) (string, error) {
+	if !strings.Contains(x, `\`) {
+		return x, nil
+	}
+
+	var b strings.Builder
+	for len(x) > 0 {
+		if strings.HasPrefix(x, `\"`) || strings.HasPrefix(x, `\'`) {
+			b.WriteByte(x[1])
+			x = x[2:]
+			continue
+		}
+		r, multibyte, tail, err := strconv.UnquoteChar(x, 0)
+		if err != nil {
+			return "", err
+		}
+		if r < 0x80 && !multibyte {
+			b.WriteByte(byte(r))
+		} else {
+			b.WriteRune(r)
+		}
+		x = tail
+	}
+	return b.String(), nil
+}
+
+// isPrintable reports whether r may appear in a value checked by StrictText: tab
+// is allowed, other control characters are not.
+func isPrintable(r rune) bool {
+	return r == '\t' || !unicode.IsControl(r)
+}
+
+// StrictText rejects values which are not valid UTF-8 or which contain control
+// characters (including NUL) other than tab.
+func StrictText() Option {
+	return decodeWith(func(x string) (string, error) {
+		if !utf8.ValidString(x) {
+			return "", errors.New("invalid UTF-8")
+		}
+		if i := strings.IndexFunc(x, func(r rune) bool { return !isPrintable(r) }); i >= 0 {
+			r, _ := utf8.DecodeRuneInString(x[i:])
+			return "", fmt.Errorf("control character %U at offset %d", r, i)
+		}
+		return x, nil
+	})
+}
+
+// SanitizeText removes invalid UTF-8 and control characters (including NUL) other
+// than tab from values, and emits a warning (see OnWarning) if any were removed.
+func SanitizeText() Option {
+	return func(v *Var) {
+		v.decode = append(v.decode, func(x string, warn func(string)) (string, error) {
+			y := strings.Map(func(r rune) rune {
+				if r == utf8.RuneError || !isPrintable(r) {
+					return -1
+				}
+				return r
+			}, x)
+			if y != x {
+				warn(fmt.Sprintf("removed %d invalid or control bytes", len(x)-len(y)))
+			}
+			return y, nil
+		})
+	}
+}
+
+// Lower converts values to lower case before they are set.
+func Lower() Option {
+	return decodeWith(func(x string) (string, error) {
+		return strings.ToLower(x), nil
+	})
+}
+
+// CanonicalHost converts host names, optionally with a port (i.e. "Example.COM.:80"),
+// to canonical form before they are set: lower case without a trailing dot.
+func CanonicalHost() Option {
+	return decodeWith(func(x string) (string, error) {
+		host, port := x, ""
+		if h, p, err := net.SplitHostPort(x); err == nil {
+			host, port = h, p
+		}
+		host = strings.TrimSuffix(strings.ToLower(host), ".")
+		if port != "" {
+			return net.JoinHostPort(host, port), nil
+		}
+		return host, nil
+	})
+}
+
+// CleanPath converts file paths to their shortest equivalent form using filepath.Clean
+// before they are set, removing trailing slashes and "." and ".." elements.  Empty values
+// are left unchanged.
+func CleanPath() Option {
+	return decodeWith(func(x string) (string, error) {
+		if x == "" {
+			return x, nil
+		}
+		return filepath.Clean(x), nil
+	})
+}
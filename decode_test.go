@@ -0,0 +1,165 @@
+package env_test
+
+import (
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestURLDecode(t *testing.T) {
+	tests := []struct {
+		in      string
+		out     string
+		wantErr bool
+	}{
+		{"abc", "abc", false},
+		{"a%20b", "a b", false},
+		{"a+b%2Fc", "a+b/c", false},
+
+		{"a%2", "", true},
+		{"a%zz", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			s := vs.String("VALUE", "url decode test", env.URLDecode())
+
+			if err := vs.Parse(testGetter{"VALUE": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if *s != tt.out {
+				t.Errorf(" = %q, expected %q", *s, tt.out)
+			}
+		})
+	}
+}
+
+func TestUnescape(t *testing.T) {
+	tests := []struct {
+		in      string
+		out     string
+		wantErr bool
+	}{
+		{"abc", "abc", false},
+		{`a\nb`, "a\nb", false},
+		{`a\tb\\c`, "a\tb\\c", false},
+		{`say \"hi\" it's`, `say "hi" it's`, false},
+		{`caf\u00e9 é`, "café é", false},
+		{`\x41`, "A", false},
+
+		{`a\`, "", true},
+		{`a\q`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			s := vs.String("VALUE", "unescape test", env.Unescape())
+
+			if err := vs.Parse(testGetter{"VALUE": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if *s != tt.out {
+				t.Errorf(" = %q, expected %q", *s, tt.out)
+			}
+		})
+	}
+}
+
+func TestStrictText(t *testing.T) {
+	tests := []struct {
+		in      string
+		out     string
+		wantErr bool
+	}{
+		{"abc", "abc", false},
+		{"a\tb", "a\tb", false},
+		{"café", "café", false},
+
+		{"a\x00b", "", true},
+		{"a\nb", "", true},
+		{"a\x7fb", "", true},
+		{"a\u0085b", "", true},
+		{"a\xffb", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			s := vs.String("VALUE", "strict text test", env.StrictText())
+
+			if err := vs.Parse(testGetter{"VALUE": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if *s != tt.out {
+				t.Errorf(" = %q, expected %q", *s, tt.out)
+			}
+		})
+	}
+}
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		in       string
+		out      string
+		warnings int
+	}{
+		{"abc", "abc", 0},
+		{"a\tb", "a\tb", 0},
+		{"café", "café", 0},
+		{"a\x00b", "ab", 1},
+		{"a\r\nb\x7f", "ab", 1},
+		{"a\xffb", "ab", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			s := vs.String("VALUE", "sanitize text test", env.SanitizeText())
+
+			warnings := 0
+			if err := vs.Parse(testGetter{"VALUE": tt.in}, env.OnWarning(func(string) { warnings++ })); err != nil {
+				t.Errorf("vs.Parse() = %v, expected nil", err)
+			}
+			if *s != tt.out || warnings != tt.warnings {
+				t.Errorf(" = (%q, %d warnings), expected (%q, %d warnings)", *s, warnings, tt.out, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestNormalise(t *testing.T) {
+	tests := []struct {
+		opt env.Option
+		in  string
+		out string
+	}{
+		{env.Lower(), "JSON", "json"},
+		{env.Lower(), "Mixed Case", "mixed case"},
+
+		{env.CanonicalHost(), "Example.COM", "example.com"},
+		{env.CanonicalHost(), "example.com.", "example.com"},
+		{env.CanonicalHost(), "Example.com.:8080", "example.com:8080"},
+		{env.CanonicalHost(), "[::1]:80", "[::1]:80"},
+
+		{env.CleanPath(), "/var/lib/", "/var/lib"},
+		{env.CleanPath(), "/var/./lib/../log", "/var/log"},
+		{env.CleanPath(), "data//dir/", "data/dir"},
+		{env.CleanPath(), "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			s := vs.String("VALUE", "normalise test", tt.opt)
+
+			if err := vs.Parse(testGetter{"VALUE": tt.in}); err != nil {
+				t.Errorf("vs.Parse() = %v, expected nil", err)
+			}
+			if *s != tt.out {
+				t.Errorf(" = %q, expected %q", *s, tt.out)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package env
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// EncryptedPrefix marks values which are decrypted by Decrypt.  It is followed by the
+// base64 encoded ciphertext.
+const EncryptedPrefix = "enc:"
+
+// ArmoredPrefix marks armored values which are passed whole to the Decrypter by Decrypt:
+// the header of age's ASCII armor (age -a).
+const ArmoredPrefix = "-----BEGIN AGE ENCRYPTED FILE-----"
+
+// Decrypter decrypts values for Decrypt.  Implementations can wrap an age identity or
+// a cloud KMS key, or use AESDecrypter for a local key.
+type Decrypter interface {
+	// Decrypt returns the plaintext of ciphertext.
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// DecrypterFunc is an adapter to allow the use of ordinary functions as a Decrypter.
+type DecrypterFunc func(ctx context.Context, ciphertext []byte) ([]byte, error)
+
+// Decrypt implements Decrypter.
+func (f DecrypterFunc) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return f(ctx, ciphertext)
+}
+
+// decryptGetter is a Getter which decrypts the encrypted values of another Getter.
+type decryptGetter struct {
+	g Getter
+	d Decrypter
+}
+
+func (g decryptGetter) Get(x string) (string, bool) {
+	z, ok, _ := g.GetContext(context.Background(), x)
+	return z, ok
+}
+
+func (g decryptGetter) GetContext(ctx context.Context, x string) (string, bool, error) {
+	z, ok, err := getContext(ctx, g.g, x)
+	if !ok || err != nil {
+		return z, ok, err
+	}
+
+	var ciphertext []byte
+	switch {
+	case strings.HasPrefix(z, EncryptedPrefix):
+		ciphertext, err = base64.StdEncoding.DecodeString(strings.TrimSpace(z[len(EncryptedPrefix):]))
+		if err != nil {
+			return "", false, fmt.Errorf("env %v: invalid encrypted value: %v", x, err)
+		}
+	case strings.HasPrefix(strings.TrimSpace(z), ArmoredPrefix):
+		ciphertext = []byte(z)
+	default:
+		return z, true, nil
+	}
+
+	b, err := g.d.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return "", false, fmt.Errorf("env %v: could not decrypt value: %v", x, err)
+	}
+	return string(b), true, nil
+}
+
+// Decrypt returns a Getter which decrypts encrypted values from g using d, so that
+// encrypted values can be committed to manifests and .env files.  Values are
+// encrypted if they begin with EncryptedPrefix followed by base64 encoded ciphertext
+// (see EncryptAES), or are armored (begin with ArmoredPrefix, like the output of
+// age -a), in which case the whole value is passed to d.  Other values, including PEM
+// certificates and keys, are returned unchanged.
+//
+// The returned Getter is also a ContextGetter, which reports decryption errors.  Get
+// reports them as unset variables.
+func Decrypt(g Getter, d Decrypter) Getter {
+	return decryptGetter{g: g, d: d}
+}
+
+// aesGCM returns an AES-GCM AEAD using key, which must be 16, 24 or 32 bytes.
+func aesGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts b with a random nonce, which is prepended to the result.
+func seal(aead cipher.AEAD, b []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, b, nil), nil
+}
+
+// open decrypts b as returned by seal.
+func open(aead cipher.AEAD, b []byte) ([]byte, error) {
+	if len(b) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	return aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], nil)
+}
+
+// AESDecrypter returns a Decrypter for values encrypted by EncryptAES with key, which
+// must be 16, 24 or 32 bytes.
+func AESDecrypter(key []byte) Decrypter {
+	return DecrypterFunc(func(ctx context.Context, ciphertext []byte) ([]byte, error) {
+		aead, err := aesGCM(key)
+		if err != nil {
+			return nil, err
+		}
+		return open(aead, ciphertext)
+	})
+}
+
+// EncryptAES encrypts plaintext with key using AES-GCM, and returns it as a value for
+// Decrypt with AESDecrypter (EncryptedPrefix followed by base64 encoded ciphertext).
+func EncryptAES(key []byte, plaintext string) (string, error) {
+	aead, err := aesGCM(key)
+	if err != nil {
+		return "", err
+	}
+	b, err := seal(aead, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(b), nil
+}
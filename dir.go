@@ -0,0 +1,94 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DirGetter returns a Getter which retrieves each variable from the file of the same
+// name in the directory at path, with a single trailing newline removed.  This is the
+// layout used by Kubernetes Secret and ConfigMap volumes:
+//
+//	/etc/secrets/DB_PASSWORD    ; DB_PASSWORD=<contents>
+//
+// Files are read on each call to Get, so values updated in the mounted volume are seen
+// when the VarSet is next parsed.  Names which are not plain file names (i.e. contain a
+// path separator or begin with a dot, like the ..data links in Kubernetes volumes) are
+// never found.
+//
+// The returned Getter is also a ContextGetter, which reports errors reading files other
+// than a missing file.
+func DirGetter(path string) Getter {
+	return dirGetter{path: path}
+}
+
+// DirGetterFunc returns a Getter which retrieves each variable from a file in the
+// directory at path, in the same way as DirGetter, with the file name given by fn (i.e.
+// strings.ToLower) applied to the variable name.
+func DirGetterFunc(path string, fn func(name string) string) Getter {
+	return dirGetter{path: path, fn: fn}
+}
+
+// DockerSecretsDir is the directory where Docker Swarm and Compose mount secrets.
+const DockerSecretsDir = "/run/secrets"
+
+// DockerSecrets returns a Getter which retrieves variables from the secrets mounted by
+// Docker Swarm or Compose in DockerSecretsDir, using the conventional lower-case file
+// names: MY_VAR is read from /run/secrets/my_var (see DirGetterFunc for other names).
+func DockerSecrets() Getter {
+	return DirGetterFunc(DockerSecretsDir, strings.ToLower)
+}
+
+type dirGetter struct {
+	path string
+	fn   func(string) string // maps names to file names, nil for the identity
+}
+
+// Get implements Getter.
+func (d dirGetter) Get(name string) (string, bool) {
+	z, ok, _ := d.GetContext(context.Background(), name)
+	return z, ok
+}
+
+// GetContext implements ContextGetter.
+func (d dirGetter) GetContext(ctx context.Context, name string) (string, bool, error) {
+	if d.fn != nil {
+		name = d.fn(name)
+	}
+	if name == "" || name[0] == '.' || strings.ContainsAny(name, `/\`) {
+		return "", false, nil
+	}
+	z, err := readFileValue(filepath.Join(d.path, name))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return z, true, nil
+}
+
+// readFileValue returns the contents of the regular file at path, with a single
+// trailing newline removed.
+func readFileValue(path string) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if fi.IsDir() {
+		return "", errors.New(path + ": is a directory")
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	z := string(b)
+	if strings.HasSuffix(z, "\n") {
+		z = strings.TrimSuffix(z[:len(z)-1], "\r")
+	}
+	return z, nil
+}
@@ -0,0 +1,99 @@
+package env_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestDirGetter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"DB_PASSWORD": "s3cret\n",
+		"CRLF":        "a\r\n",
+		"TWO_LINES":   "a\nb\n\n",
+		"EMPTY":       "",
+		".hidden":     "x",
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "SUBDIR"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		out  string
+		ok   bool
+	}{
+		{"DB_PASSWORD", "s3cret", true},
+		{"CRLF", "a", true},
+		{"TWO_LINES", "a\nb\n", true},
+		{"EMPTY", "", true},
+		{"MISSING", "", false},
+		{".hidden", "", false},
+		{"../" + filepath.Base(dir) + "/DB_PASSWORD", "", false},
+		{"SUBDIR", "", false},
+	}
+
+	g := env.DirGetter(dir)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, ok := g.Get(tt.name)
+			if out != tt.out || ok != tt.ok {
+				t.Errorf("Get(%q) = (%q, %v), expected (%q, %v)", tt.name, out, ok, tt.out, tt.ok)
+			}
+		})
+	}
+
+	if _, _, err := g.(env.ContextGetter).GetContext(context.Background(), "SUBDIR"); err == nil {
+		t.Errorf("expected error for directory")
+	}
+
+	vs := env.NewVarSet("")
+	password := vs.String("DB_PASSWORD", "password")
+	if err := vs.Parse(g); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+	if *password != "s3cret" {
+		t.Errorf("got %q, expected %q", *password, "s3cret")
+	}
+}
+
+func TestDirGetterFunc(t *testing.T) {
+	dir, err := ioutil.TempDir("", "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "db_password"), []byte("s3cret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	g := env.DirGetterFunc(dir, strings.ToLower)
+	if z, ok := g.Get("DB_PASSWORD"); !ok || z != "s3cret" {
+		t.Errorf("Get(%q) = (%q, %v), expected (%q, true)", "DB_PASSWORD", z, ok, "s3cret")
+	}
+	if z, ok := g.Get("API_KEY"); ok {
+		t.Errorf("Get(%q) = (%q, %v), expected unset", "API_KEY", z, ok)
+	}
+
+	g = env.DirGetterFunc(dir, func(string) string { return "../db_password" })
+	if z, ok := g.Get("DB_PASSWORD"); ok {
+		t.Errorf("Get(%q) = (%q, %v), expected unset for path outside directory", "DB_PASSWORD", z, ok)
+	}
+}
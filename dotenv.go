@@ -0,0 +1,289 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// DotenvFile returns a Getter for the variables defined in the .env file at path
+// (see Dotenv).
+func DotenvFile(path string) (Getter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	g, err := Dotenv(f)
+	if err != nil {
+		return nil, fmt.Errorf("%v:%v", path, err)
+	}
+	return g, nil
+}
+
+// Dotenv returns a Getter for the variables defined in .env syntax read from r:
+//
+//	# comment
+//	NAME=value            # unquoted values are trimmed; " #" starts a comment,
+//	                      # as does "#" at the start of the value
+//	export NAME=value     # the export prefix is ignored
+//	NAME='literal $value' # single quoted values are used as given
+//	NAME="a\nb"           # double quoted values interpret \n, \r, \t, \", \\ and \$,
+//	                      # and may span several lines
+//
+// Later definitions of a name replace earlier ones.  Variables are not expanded.
+func Dotenv(r io.Reader) (MapGetter, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	g := make(MapGetter)
+	p := dotenvParser{s: strings.ReplaceAll(string(b), "\r\n", "\n"), line: 1}
+	for {
+		p.skipSpace()
+		if p.s == "" {
+			return g, nil
+		}
+		if p.s[0] == '#' || p.s[0] == '\n' {
+			p.skipLine()
+			continue
+		}
+
+		line := p.line
+		name, value, err := p.assignment()
+		if err != nil {
+			return nil, fmt.Errorf("%d: %v", line, err)
+		}
+		g[name] = value
+	}
+}
+
+// dotenvParser parses the remaining input s, which starts on line.
+type dotenvParser struct {
+	s    string
+	line int
+}
+
+func (p *dotenvParser) advance(n int) {
+	p.line += strings.Count(p.s[:n], "\n")
+	p.s = p.s[n:]
+}
+
+// skipSpace skips spaces and tabs, but not line endings.
+func (p *dotenvParser) skipSpace() {
+	p.advance(len(p.s) - len(strings.TrimLeft(p.s, " \t")))
+}
+
+// skipLine skips the rest of the line, including the line ending.
+func (p *dotenvParser) skipLine() {
+	if i := strings.IndexByte(p.s, '\n'); i >= 0 {
+		p.advance(i + 1)
+		return
+	}
+	p.advance(len(p.s))
+}
+
+// assignment parses [export] NAME=value up to the end of the line.
+func (p *dotenvParser) assignment() (string, string, error) {
+	if strings.HasPrefix(p.s, "export ") || strings.HasPrefix(p.s, "export\t") {
+		p.advance(len("export"))
+		p.skipSpace()
+	}
+
+	i := strings.IndexFunc(p.s, func(r rune) bool {
+		return !(r == '_' || r == '.' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	})
+	if i < 0 {
+		i = len(p.s)
+	}
+	name := p.s[:i]
+	if name == "" || '0' <= name[0] && name[0] <= '9' {
+		return "", "", errors.New("invalid variable name")
+	}
+	p.advance(i)
+
+	p.skipSpace()
+	if p.s == "" || p.s[0] != '=' {
+		return "", "", fmt.Errorf("expected = after %v", name)
+	}
+	p.advance(1)
+	p.skipSpace()
+
+	var value string
+	switch {
+	case strings.HasPrefix(p.s, "'"):
+		i := strings.IndexByte(p.s[1:], '\'')
+		if i < 0 {
+			return "", "", fmt.Errorf("unterminated quote in %v", name)
+		}
+		value = p.s[1 : i+1]
+		p.advance(i + 2)
+	case strings.HasPrefix(p.s, `"`):
+		var err error
+		if value, err = p.doubleQuoted(); err != nil {
+			return "", "", fmt.Errorf("%v in %v", err, name)
+		}
+	default:
+		end := strings.IndexByte(p.s, '\n')
+		if end < 0 {
+			end = len(p.s)
+		}
+		value = p.s[:end]
+		if strings.HasPrefix(value, "#") {
+			value = ""
+		} else if i := strings.Index(value, " #"); i >= 0 {
+			value = value[:i]
+		} else if i := strings.Index(value, "\t#"); i >= 0 {
+			value = value[:i]
+		}
+		value = strings.TrimSpace(value)
+		p.advance(end)
+	}
+
+	// Only a comment may follow the value.
+	p.skipSpace()
+	if p.s != "" && p.s[0] != '\n' && p.s[0] != '#' {
+		return "", "", fmt.Errorf("unexpected text after value of %v", name)
+	}
+	p.skipLine()
+	return name, value, nil
+}
+
+// doubleQuoted parses a double quoted value, interpreting escapes.
+func (p *dotenvParser) doubleQuoted() (string, error) {
+	var b strings.Builder
+	for i := 1; i < len(p.s); i++ {
+		switch c := p.s[i]; c {
+		case '"':
+			p.advance(i + 1)
+			return b.String(), nil
+		case '\\':
+			if i+1 == len(p.s) {
+				return "", errors.New("unterminated quote")
+			}
+			i++
+			switch c := p.s[i]; c {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$':
+				b.WriteByte(c)
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(c)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", errors.New("unterminated quote")
+}
+
+// dotenvQuote returns x quoted for use as a value in a .env file, if needed.
+func dotenvQuote(x string) string {
+	safe := x != "" && strings.IndexFunc(x, func(r rune) bool {
+		return !(strings.ContainsRune("_-./:,@+=%", r) || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) < 0
+	if safe {
+		return x
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(x) + `"`
+}
+
+// WriteDotenv writes the value of each variable in the set to w in .env format,
+// preceded by its usage as a comment, for snapshotting the effective configuration.
+// Values are written as they were given when the set was last parsed (or the default
+// if the variable was unset), so that the output can be compared with the environment
+// (see Audit); variables which have not been parsed are written with their current
+// value.  The values of variables marked Secret are left empty unless includeSecrets
+// is true.  The output can be read by Dotenv.
+func (v *VarSet) WriteDotenv(w io.Writer, includeSecrets bool) error {
+	var err error
+	first := true
+	v.Visit(func(x *Var) {
+		if err != nil {
+			return
+		}
+		if !first {
+			_, err = io.WriteString(w, "\n")
+		}
+		first = false
+
+		value := x.Value.String()
+		switch {
+		case x.source != "" && !(x.stdin && x.raw == "-"):
+			value = x.raw
+		case x.source == "" && x.def != nil:
+			value = x.def()
+		}
+		comment := x.Usage
+		if x.secret && !includeSecrets {
+			value = ""
+			comment += " (secret, redacted)"
+		}
+		if err == nil {
+			_, err = fmt.Fprintf(w, "# %v\n%v=%v\n", strings.Replace(comment, "\n", " ", -1), x.Name, dotenvQuote(value))
+		}
+	})
+	return err
+}
+
+// WriteExample writes a template for a .env file for the set (i.e. .env.example) to w,
+// with every variable preceded by its usage, constraint and whether it is required as
+// a comment.  Each variable is given its default, or else the example value from
+// Describe as a placeholder.  Optional variables are commented out, and secret
+// variables are always left empty.
+func (v *VarSet) WriteExample(w io.Writer) error {
+	var err error
+	first := true
+	v.Visit(func(x *Var) {
+		if err != nil {
+			return
+		}
+		if !first {
+			_, err = io.WriteString(w, "\n")
+		}
+		first = false
+
+		constraint, example := Describe(x.Value)
+		var notes []string
+		if !x.optional && x.def == nil {
+			notes = append(notes, "required")
+		}
+		if x.secret {
+			notes = append(notes, "secret")
+		}
+		if constraint != "" {
+			notes = append(notes, constraint)
+		}
+		comment := x.Usage
+		if len(notes) > 0 {
+			comment += " (" + strings.Join(notes, ", ") + ")"
+		}
+
+		value := example
+		if x.def != nil {
+			value = x.def()
+		}
+		if x.secret {
+			value = ""
+		}
+		assign := ""
+		if x.optional && x.def == nil {
+			assign = "# "
+		}
+		if err == nil {
+			_, err = fmt.Fprintf(w, "# %v\n%v%v=%v\n", strings.Replace(comment, "\n", " ", -1), assign, x.Name, dotenvQuote(value))
+		}
+	})
+	return err
+}
@@ -0,0 +1,149 @@
+package env_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestDotenv(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		out     env.MapGetter
+		wantErr bool
+	}{
+		{"empty", "", env.MapGetter{}, false},
+		{"simple", "A=1\nB=two words\n", env.MapGetter{"A": "1", "B": "two words"}, false},
+		{"comments", "# comment\n\nA=1 # trailing\nB=x#y\n  # indented\n", env.MapGetter{"A": "1", "B": "x#y"}, false},
+		{"export", "export A=1\nexport\tB = 2\n", env.MapGetter{"A": "1", "B": "2"}, false},
+		{"single quotes", `A='$x \n "y"' # c`, env.MapGetter{"A": `$x \n "y"`}, false},
+		{"double quotes", `A="a\nb\t\"c\" \\ \$d \x"`, env.MapGetter{"A": "a\nb\t\"c\" \\ $d \\x"}, false},
+		{"multiline", "A=\"line 1\nline 2\"\nB=3", env.MapGetter{"A": "line 1\nline 2", "B": "3"}, false},
+		{"crlf", "A=1\r\nB=\"2\"\r\n", env.MapGetter{"A": "1", "B": "2"}, false},
+		{"empty value", "A=\nB=''\nC=\"\"", env.MapGetter{"A": "", "B": "", "C": ""}, false},
+		{"empty value with comment", "A= # note\nB=\t# note\nC=#note\n", env.MapGetter{"A": "", "B": "", "C": ""}, false},
+		{"redefined", "A=1\nA=2", env.MapGetter{"A": "2"}, false},
+
+		{"no equals", "A\n", nil, true},
+		{"bad name", "1A=x\n", nil, true},
+		{"unterminated single", "A='x\n", nil, true},
+		{"unterminated double", "A=\"x\n", nil, true},
+		{"trailing text", "A='x' y\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := env.Dotenv(strings.NewReader(tt.in))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("env.Dotenv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(g, tt.out) {
+				t.Errorf("env.Dotenv() = %q, expected %q", g, tt.out)
+			}
+		})
+	}
+}
+
+func TestDotenvFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, ".env")
+	if err := ioutil.WriteFile(path, []byte("SVC_WORKERS=4\nSVC_NAME=\"a b\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := env.DotenvFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error from DotenvFile: %v", err)
+	}
+	vs := env.NewVarSet("svc")
+	workers := vs.Int("WORKERS", "dotenv test")
+	name := vs.String("NAME", "dotenv test")
+	if err := vs.Parse(g); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *workers != 4 || *name != "a b" {
+		t.Errorf("got (%d, %q), expected (4, \"a b\")", *workers, *name)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("A=1\nB\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.DotenvFile(path); err == nil || !strings.Contains(err.Error(), ".env:2:") {
+		t.Errorf("got error %v, expected error on line 2", err)
+	}
+}
+
+func TestWriteDotenv(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	vs.Int("WORKERS", "number of workers")
+	vs.String("NAME", "display name")
+	vs.String("TOKEN", "api token", env.Secret())
+
+	in := testGetter{"SVC_WORKERS": "4", "SVC_NAME": "a \"b\" $c\n", "SVC_TOKEN": "t0k3n"}
+	if err := vs.Parse(in); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := vs.WriteDotenv(&buf, false); err != nil {
+		t.Fatalf("unexpected error from WriteDotenv: %v", err)
+	}
+	expected := "# number of workers\nSVC_WORKERS=4\n\n# display name\nSVC_NAME=\"a \\\"b\\\" \\$c\\n\"\n\n# api token (secret, redacted)\nSVC_TOKEN=\"\"\n"
+	if buf.String() != expected {
+		t.Errorf("got:\n%v\nexpected:\n%v", buf.String(), expected)
+	}
+
+	buf.Reset()
+	if err := vs.WriteDotenv(&buf, true); err != nil {
+		t.Fatalf("unexpected error from WriteDotenv: %v", err)
+	}
+	g, err := env.Dotenv(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error reading output: %v", err)
+	}
+	if !reflect.DeepEqual(g, env.MapGetter(in)) {
+		t.Errorf("round trip got %q, expected %q", g, in)
+	}
+}
+
+func TestWriteExample(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	vs.Int("WORKERS", "number of workers", env.Default("4"))
+	vs.BindAddr("ADDR", "listen address")
+	vs.String("TOKEN", "api token", env.Secret())
+	vs.Bool("DEBUG", "debug logging", env.Optional())
+	vs.String("GREETING", "greeting", env.Default("hello world"))
+
+	var buf bytes.Buffer
+	if err := vs.WriteExample(&buf); err != nil {
+		t.Fatalf("unexpected error from WriteExample: %v", err)
+	}
+	expected := "# number of workers (integer)\nSVC_WORKERS=4\n\n" +
+		"# listen address (required, bind address (host:port))\nSVC_ADDR=:8080\n\n" +
+		"# api token (required, secret)\nSVC_TOKEN=\"\"\n\n" +
+		"# debug logging (boolean)\n# SVC_DEBUG=true\n\n" +
+		"# greeting\nSVC_GREETING=\"hello world\"\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("got:\n%v\nexpected:\n%v", got, expected)
+	}
+
+	g, err := env.Dotenv(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error reading example: %v", err)
+	}
+	if _, ok := g["SVC_DEBUG"]; ok {
+		t.Errorf("expected optional variable to be commented out")
+	}
+}
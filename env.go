@@ -3,10 +3,14 @@
 package env // import "code.sajari.com/env"
 
 import (
+	"encoding"
 	"errors"
 	"fmt"
+	"math/big"
+	"net"
 	"os"
 	"path"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -17,9 +21,112 @@ type Var struct {
 	Name  string // name
 	Usage string // help message
 	Value Value  // value as set
+	Scope Scope  // behaviour on reload
+
+	raw         string        // value last successfully parsed
+	source      string        // name which supplied raw, empty for a default
+	prefix      string        // prefix of the VarSet, applied to aliases
+	fallbacks   []string      // names to look up, in order, if Name is unset
+	aliases     []string      // fallbacks prefixed by Alias or Deprecated
+	deprecated  []string      // fallbacks which warn when used
+	optional    bool          // whether the variable can be left unset
+	def         func() string // default value used if the variable is unset
+	decode      []decoder     // applied in order before Value.Set
+	stdin       bool          // whether "-" means read the value from stdin
+	secret      bool          // whether the value is secret
+	criticality Criticality   // how failures are handled
+}
+
+// lookup retrieves the value of the variable from the Getter, trying
+// fallback names in order if it is not set, and then the names returned by
+// legacy (see Legacy), if it is not nil.  It returns the value and the name
+// which supplied it.
+func (x *Var) lookup(g Getter, legacy func(string) []string) (string, string, bool) {
+	if z, ok := g.Get(x.Name); ok {
+		return z, x.Name, true
+	}
+	for _, name := range x.fallbacks {
+		if z, ok := g.Get(name); ok {
+			return z, name, true
+		}
+	}
+	if legacy != nil {
+		for _, name := range legacy(x.Name) {
+			if z, ok := g.Get(name); ok {
+				return z, name, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// isAlias reports whether name is a fallback which was prefixed by Alias or Deprecated.
+func (x *Var) isAlias(name string) bool {
+	for _, a := range x.aliases {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isFallback reports whether name is one of the fallbacks of the variable.
+func (x *Var) isFallback(name string) bool {
+	for _, f := range x.fallbacks {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// set applies the decoders of the variable to z and then sets its value.
+// Warnings from decoders and values are passed to warn.
+func (x *Var) set(z string, warn func(string)) error {
+	for _, fn := range x.decode {
+		var err error
+		if z, err = fn(z, warn); err != nil {
+			return err
+		}
+	}
+	if ws, ok := x.Value.(warnSetter); ok {
+		return ws.setWarn(z, warn)
+	}
+	return x.Value.Set(z)
+}
+
+// warnSetter is an optional interface implemented by values which report warnings,
+// such as a certificate close to expiry, when they are set by Parse.
+type warnSetter interface {
+	setWarn(z string, warn func(string)) error
+}
+
+// isDeprecated reports whether name is a deprecated name of the variable.
+func (x *Var) isDeprecated(name string) bool {
+	for _, d := range x.deprecated {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Secret reports whether the variable was marked with the Secret option.
+func (x *Var) Secret() bool {
+	return x.secret
+}
+
+// Source returns the name which supplied the value of the variable when it was
+// last parsed: either Name or one of its aliases or fallbacks.  It returns the
+// empty string if the variable has not been parsed or its default was used.
+func (x *Var) Source() string {
+	return x.source
 }
 
 // Value is the interface to the dynamic value stored in Var.
+//
+// Value has the same method set as flag.Value, so implementations can be
+// used with both packages (see FromFlagValue and ToFlagValue).
 type Value interface {
 	// String is a string representation of the stored value.
 	String() string
@@ -29,6 +136,25 @@ type Value interface {
 	Set(string) error
 }
 
+// Describer is an optional interface implemented by values which
+// place constraints on the input they accept.
+type Describer interface {
+	// Constraint describes the values accepted by Set, i.e. "bind address (host:port)".
+	Constraint() string
+
+	// Example returns an example of a value accepted by Set.
+	Example() string
+}
+
+// Describe returns the constraint and example for v if it implements
+// Describer, otherwise it returns empty strings.
+func Describe(v Value) (constraint, example string) {
+	if d, ok := v.(Describer); ok {
+		return d.Constraint(), d.Example()
+	}
+	return "", ""
+}
+
 type stringValue string
 
 func newStringValue(x string, p *string) *stringValue {
@@ -45,6 +171,8 @@ func (v *stringValue) String() string {
 	return string(*v)
 }
 
+func (v *stringValue) typeName() string { return "string" }
+
 type intValue int
 
 func newIntValue(x int, p *int) *intValue {
@@ -67,6 +195,11 @@ func (v *intValue) String() string {
 	return strconv.Itoa(int(*v))
 }
 
+func (v *intValue) typeName() string { return "int" }
+
+func (v *intValue) Constraint() string { return "integer" }
+func (v *intValue) Example() string    { return "42" }
+
 type durationValue time.Duration
 
 func newDurationValue(x time.Duration, p *time.Duration) *durationValue {
@@ -84,6 +217,11 @@ func (v *durationValue) String() string {
 	return time.Duration(*v).String()
 }
 
+func (v *durationValue) typeName() string { return "duration" }
+
+func (v *durationValue) Constraint() string { return "duration" }
+func (v *durationValue) Example() string    { return "1m30s" }
+
 type boolValue bool
 
 func newBoolValue(x bool, p *bool) *boolValue {
@@ -106,6 +244,239 @@ func (v *boolValue) String() string {
 	return strconv.FormatBool(bool(*v))
 }
 
+func (v *boolValue) typeName() string { return "bool" }
+
+func (v *boolValue) Constraint() string { return "boolean" }
+func (v *boolValue) Example() string    { return "true" }
+
+type funcValue struct {
+	fn  func(string) error
+	raw string
+}
+
+func (v *funcValue) Set(x string) error {
+	if err := v.fn(x); err != nil {
+		return err
+	}
+	v.raw = x
+	return nil
+}
+
+func (v *funcValue) String() string {
+	return v.raw
+}
+
+func (v *funcValue) typeName() string { return "string" }
+
+type textValue struct {
+	p encoding.TextUnmarshaler
+}
+
+func (v textValue) Set(x string) error {
+	return v.p.UnmarshalText([]byte(x))
+}
+
+func (v textValue) String() string {
+	if m, ok := v.p.(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+	return fmt.Sprint(v.p)
+}
+
+// typeName returns the name of the type which the value unmarshals into, i.e. "net.IP".
+func (v textValue) typeName() string {
+	t := reflect.TypeOf(v.p)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.String()
+}
+
+type locationValue struct {
+	p **time.Location
+}
+
+func newLocationValue(x *time.Location, p **time.Location) *locationValue {
+	*p = x
+	return &locationValue{p}
+}
+
+func (v *locationValue) Set(x string) error {
+	if x == "" {
+		return errors.New("empty location")
+	}
+	l, err := time.LoadLocation(x)
+	if err != nil {
+		return err
+	}
+	*v.p = l
+	return nil
+}
+
+func (v *locationValue) String() string {
+	return (*v.p).String()
+}
+
+func (v *locationValue) typeName() string { return "location" }
+
+func (v *locationValue) Constraint() string { return "IANA time zone" }
+func (v *locationValue) Example() string    { return "Australia/Sydney" }
+
+type fileModeValue os.FileMode
+
+func newFileModeValue(x os.FileMode, p *os.FileMode) *fileModeValue {
+	*p = x
+	return (*fileModeValue)(p)
+}
+
+func (v *fileModeValue) Set(x string) error {
+	n, err := strconv.ParseUint(x, 8, 32)
+	if err != nil {
+		return errors.New("parsing " + strconv.Quote(x) + ": invalid octal file mode")
+	}
+	if n > 07777 {
+		return errors.New("parsing " + strconv.Quote(x) + ": file mode out of range")
+	}
+
+	m := os.FileMode(n & 0777)
+	if n&04000 != 0 {
+		m |= os.ModeSetuid
+	}
+	if n&02000 != 0 {
+		m |= os.ModeSetgid
+	}
+	if n&01000 != 0 {
+		m |= os.ModeSticky
+	}
+	*v = fileModeValue(m)
+	return nil
+}
+
+func (v *fileModeValue) String() string {
+	m := os.FileMode(*v)
+	n := uint32(m.Perm())
+	if m&os.ModeSetuid != 0 {
+		n |= 04000
+	}
+	if m&os.ModeSetgid != 0 {
+		n |= 02000
+	}
+	if m&os.ModeSticky != 0 {
+		n |= 01000
+	}
+	return fmt.Sprintf("%04o", n)
+}
+
+func (v *fileModeValue) typeName() string { return "mode" }
+
+func (v *fileModeValue) Constraint() string { return "octal file mode" }
+func (v *fileModeValue) Example() string    { return "0640" }
+
+type macValue net.HardwareAddr
+
+func newMACValue(x net.HardwareAddr, p *net.HardwareAddr) *macValue {
+	*p = x
+	return (*macValue)(p)
+}
+
+func (v *macValue) Set(x string) error {
+	m, err := net.ParseMAC(x)
+	if err != nil {
+		return err
+	}
+	*v = macValue(m)
+	return nil
+}
+
+func (v *macValue) String() string {
+	return net.HardwareAddr(*v).String()
+}
+
+func (v *macValue) typeName() string { return "mac" }
+
+func (v *macValue) Constraint() string { return "MAC address" }
+func (v *macValue) Example() string    { return "00:00:5e:00:53:01" }
+
+type bigIntValue struct {
+	p    **big.Int
+	base int
+}
+
+func (v *bigIntValue) Set(x string) error {
+	n, ok := new(big.Int).SetString(x, v.base)
+	if !ok {
+		return errors.New("parsing " + strconv.Quote(x) + ": invalid integer")
+	}
+	*v.p = n
+	return nil
+}
+
+func (v *bigIntValue) String() string {
+	if *v.p == nil {
+		return ""
+	}
+	return (*v.p).String()
+}
+
+func (v *bigIntValue) typeName() string { return "bigint" }
+
+func (v *bigIntValue) Constraint() string {
+	if v.base == 0 {
+		return "integer (0x, 0o and 0b prefixes permitted)"
+	}
+	return fmt.Sprintf("base %d integer", v.base)
+}
+
+func (v *bigIntValue) Example() string {
+	if v.base == 0 || v.base == 10 {
+		return "123456789012345678901234567890"
+	}
+	return big.NewInt(255).Text(v.base)
+}
+
+type percentValue struct {
+	p        *float64
+	fraction bool // bare numbers are fractions of 1 rather than percentages
+}
+
+func newPercentValue(x float64, p *float64, fraction bool) *percentValue {
+	*p = x
+	return &percentValue{p: p, fraction: fraction}
+}
+
+func (v *percentValue) Set(x string) error {
+	s := strings.TrimSpace(x)
+	percent := strings.HasSuffix(s, "%")
+	if percent {
+		s = strings.TrimSpace(strings.TrimSuffix(s, "%"))
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return errors.New("parsing " + strconv.Quote(x) + ": invalid syntax")
+	}
+	if !percent && v.fraction {
+		f *= 100
+	}
+	if !(0 <= f && f <= 100) {
+		return errors.New("percentage " + strconv.Quote(x) + " out of range [0, 100]")
+	}
+	*v.p = f
+	return nil
+}
+
+func (v *percentValue) String() string {
+	return strconv.FormatFloat(*v.p, 'g', -1, 64) + "%"
+}
+
+func (v *percentValue) typeName() string { return "percent" }
+
+func (v *percentValue) Constraint() string { return "percentage (0-100%)" }
+func (v *percentValue) Example() string    { return "25%" }
+
 // NewVarSet creates a new variable set with given name.
 //
 // If name is non-empty, then all variables will have a strings.ToUpper(name)+"_"
@@ -121,18 +492,95 @@ func NewVarSet(name string) *VarSet {
 type VarSet struct {
 	name   string
 	prefix string
+	clock  Clock
+
+	vars   []*Var
+	checks []func() error
 
-	vars []*Var
+	parent *VarSet   // set which this one was added to by Sub or AddVarSet
+	subs   []*VarSet // sets added by Sub or AddVarSet
 }
 
-// Var defines a variable with the specified name and usage string.
-func (v *VarSet) Var(value Value, name, usage string) {
-	var prefix string
+// varName returns the full name of the variable name, including the set prefix.
+func (v *VarSet) varName(name string) string {
 	if v.prefix != "" {
-		prefix = v.prefix + "_"
+		return v.prefix + "_" + name
+	}
+	return name
+}
+
+// Var defines a variable with the specified name and usage string.  Var panics if the
+// variable is in a namespace reserved by another set (see ReserveNamespace).
+func (v *VarSet) Var(value Value, name, usage string, opts ...Option) {
+	v.define(value, name, usage, opts...)
+}
+
+// define is Var, returning the defined variable so its name can be used in messages.
+func (v *VarSet) define(value Value, name, usage string, opts ...Option) *Var {
+	x := &Var{Value: value, Name: v.varName(name), Usage: usage, prefix: v.prefix}
+	for _, o := range opts {
+		o(x)
+	}
+	if err := checkNamespace(v, x.Name); err != nil {
+		panic(err.Error())
 	}
-	x := &Var{Value: value, Name: prefix + name, Usage: usage}
 	v.vars = append(v.vars, x)
+	return x
+}
+
+// Sub creates a variable set whose variables are prefixed by the prefix of v and then
+// name (i.e. PARENT_CHILD_NAME), for grouping related configuration.  The variables of
+// the sub-set are parsed and visited along with those of v.
+func (v *VarSet) Sub(name string) *VarSet {
+	s := NewVarSet(name)
+	s.name = v.name + "." + name
+	s.prefix = v.varName(s.prefix)
+	s.parent = v
+	v.subs = append(v.subs, s)
+	return s
+}
+
+// AddVarSet adds the variables of other to v, so that they are parsed and visited
+// along with those of v.  Variables keep the names given by other.  This allows
+// packages to export their own VarSet for applications to merge into CmdVar.
+// It returns an error if other has already been added to a set, if any of its
+// variables has the same name as one in v, or if any is in a namespace reserved by
+// another set (see ReserveNamespace).
+func (v *VarSet) AddVarSet(other *VarSet) error {
+	if other.parent != nil {
+		return fmt.Errorf("env set %q already added to a set", other.name)
+	}
+	for p := v; p != nil; p = p.parent {
+		if p == other {
+			return fmt.Errorf("env set %q cannot be added to itself", other.name)
+		}
+	}
+
+	defined := make(map[string]bool)
+	v.Visit(func(x *Var) { defined[x.Name] = true })
+
+	var errs []error
+	other.Visit(func(x *Var) {
+		if defined[x.Name] {
+			errs = append(errs, fmt.Errorf("env %v defined in sets %q and %q", x.Name, v.name, other.name))
+		}
+	})
+	errs = append(errs, other.checkNamespaces()...)
+	if len(errs) > 0 {
+		return Errors(errs)
+	}
+
+	other.parent = v
+	v.subs = append(v.subs, other)
+	return nil
+}
+
+// Validate adds a check to be run by Parse once all variables in the set have been
+// parsed successfully.  Checks are used to validate constraints between variables,
+// i.e. that one value is less than another.  A check should return a *CheckError to
+// name the variable which is in error.
+func (v *VarSet) Validate(fn func() error) {
+	v.checks = append(v.checks, fn)
 }
 
 // Name is the name of the variable set.
@@ -146,115 +594,274 @@ func (v *VarSet) Prefix() string {
 }
 
 // Visit visits the variables in the order in which they were defined, calling fn for each.
+// The variables of sets added using Sub or AddVarSet are visited after those of v.
 func (v *VarSet) Visit(fn func(v *Var)) {
 	for _, x := range v.vars {
 		fn(x)
 	}
+	for _, s := range v.subs {
+		s.Visit(fn)
+	}
 }
 
 // String defines a string variable with specified name, usage string and validation checks.
 // The return value is the address of a string variable that stores the value of the variable.
-func (v *VarSet) String(name string, usage string) *string {
+func (v *VarSet) String(name string, usage string, opts ...Option) *string {
 	p := new(string)
-	v.Var(newStringValue("", p), name, usage)
+	v.Var(newStringValue("", p), name, usage, opts...)
 	return p
 }
 
 // StringRequired defines a required string variable with specified name and usage string.
 // The return value is the address of a string variable that stores the value of the variable.
-func (v *VarSet) StringRequired(name string, usage string) *string {
+func (v *VarSet) StringRequired(name string, usage string, opts ...Option) *string {
 	p := new(string)
 	v.Var(checkedValue{
-		fn:    isNonEmpty,
-		Value: newStringValue("", p),
-	}, name, usage)
+		fn:         isNonEmpty,
+		constraint: "non-empty string",
+		Value:      newStringValue("", p),
+	}, name, usage, opts...)
 	return p
 }
 
 // Int defines an int variable with specified name, usage string and validation checks.
 // The return value is the address of an int variable that stores the value of the variable.
-func (v *VarSet) Int(name string, usage string) *int {
+func (v *VarSet) Int(name string, usage string, opts ...Option) *int {
 	p := new(int)
-	v.Var(newIntValue(0, p), name, usage)
+	v.Var(newIntValue(0, p), name, usage, opts...)
 	return p
 }
 
 // Bool defines a bool variable with specified name, usage string and validation checks.
 // The return value is the address of a bool variable that stores the value of the variable.
-func (v *VarSet) Bool(name string, usage string) *bool {
+func (v *VarSet) Bool(name string, usage string, opts ...Option) *bool {
 	p := new(bool)
-	v.Var(newBoolValue(false, p), name, usage)
+	v.Var(newBoolValue(false, p), name, usage, opts...)
 	return p
 }
 
 // Duration defines a time.Duration variable with specified name, usage string and validation checks.
 // The return value is the address of a time.Duration variable that stores the value of the variable.
-func (v *VarSet) Duration(name string, usage string) *time.Duration {
+func (v *VarSet) Duration(name string, usage string, opts ...Option) *time.Duration {
 	p := new(time.Duration)
-	v.Var(newDurationValue(time.Duration(0), p), name, usage)
+	v.Var(newDurationValue(time.Duration(0), p), name, usage, opts...)
+	return p
+}
+
+// Func defines a variable with specified name and usage string.  Each time the variable
+// is parsed fn is called with its value, and any error returned by fn is reported by Parse.
+func (v *VarSet) Func(name, usage string, fn func(string) error, opts ...Option) {
+	v.Var(&funcValue{fn: fn}, name, usage, opts...)
+}
+
+// Text defines a variable with specified name and usage string which is parsed by
+// calling p.UnmarshalText.  If p also implements encoding.TextMarshaler then it is used
+// to format the value.  This allows types such as netip.Addr and time.Time to be used
+// directly:
+//
+//	var addr netip.Addr
+//	vs.Text("ADDR", "address to connect to", &addr)
+func (v *VarSet) Text(name, usage string, p encoding.TextUnmarshaler, opts ...Option) {
+	v.Var(textValue{p}, name, usage, opts...)
+}
+
+// Location defines a *time.Location variable with specified name and usage string.
+// The value is loaded with time.LoadLocation from an IANA time zone name (i.e. "America/New_York")
+// when the variable is parsed.
+// The return value is the address of a *time.Location variable that stores the value of the variable.
+func (v *VarSet) Location(name, usage string, opts ...Option) **time.Location {
+	p := new(*time.Location)
+	v.Var(newLocationValue(time.UTC, p), name, usage, opts...)
+	return p
+}
+
+// BigInt defines a *big.Int variable with specified name and usage string.  Values are
+// parsed with big.Int's SetString in the given base: if base is 0 then the base is
+// determined by the prefix of the value ("0x" for hexadecimal, "0o" or "0" for octal,
+// "0b" for binary, and decimal otherwise).  BigInt panics if base is not 0 or between
+// 2 and 62.
+// The return value is the address of a *big.Int variable that stores the value of the variable.
+func (v *VarSet) BigInt(name, usage string, base int, opts ...Option) **big.Int {
+	if base != 0 && (base < 2 || base > big.MaxBase) {
+		panic(fmt.Sprintf("env %v: invalid BigInt base %d, must be 0 or between 2 and %d", v.varName(name), base, big.MaxBase))
+	}
+	p := new(*big.Int)
+	v.Var(&bigIntValue{p: p, base: base}, name, usage, opts...)
+	return p
+}
+
+// FileMode defines an os.FileMode variable with specified name and usage string.  Values
+// are given in octal (i.e. "0640"), and may include the setuid (04000), setgid (02000) and
+// sticky (01000) bits.
+// The return value is the address of an os.FileMode variable that stores the value of the variable.
+func (v *VarSet) FileMode(name, usage string, opts ...Option) *os.FileMode {
+	p := new(os.FileMode)
+	v.Var(newFileModeValue(0, p), name, usage, opts...)
+	return p
+}
+
+// MAC defines a net.HardwareAddr variable with specified name and usage string, parsed
+// with net.ParseMAC.
+// The return value is the address of a net.HardwareAddr variable that stores the value of the variable.
+func (v *VarSet) MAC(name, usage string, opts ...Option) *net.HardwareAddr {
+	p := new(net.HardwareAddr)
+	v.Var(newMACValue(nil, p), name, usage, opts...)
+	return p
+}
+
+// Percent defines a float64 variable with specified name and usage string holding a
+// percentage in the range [0, 100].  Values may be given with a percent sign ("25%")
+// or as a bare percentage ("25").
+// The return value is the address of a float64 variable that stores the value of the variable.
+func (v *VarSet) Percent(name, usage string, opts ...Option) *float64 {
+	p := new(float64)
+	v.Var(newPercentValue(0, p, false), name, usage, opts...)
+	return p
+}
+
+// PercentFraction is like Percent except that bare numbers are interpreted as fractions
+// of 1, so that "0.25" and "25%" are both stored as 25.
+// The return value is the address of a float64 variable that stores the value of the variable.
+func (v *VarSet) PercentFraction(name, usage string, opts ...Option) *float64 {
+	p := new(float64)
+	v.Var(newPercentValue(0, p, true), name, usage, opts...)
 	return p
 }
 
 // BindAddr defines a string variable with specified name, usage string validated as a
 // bind address (host:port).
 // The return value is the address of a string variable that stores the value of the variable.
-func (v *VarSet) BindAddr(name, usage string) *string {
+func (v *VarSet) BindAddr(name, usage string, opts ...Option) *string {
 	p := new(string)
 	v.Var(checkedValue{
-		fn:    isBindAddr,
-		Value: newStringValue("", p),
-	}, name, usage)
+		fn:         isBindAddr,
+		constraint: "bind address (host:port)",
+		example:    ":8080",
+		Value:      newStringValue("", p),
+	}, name, usage, opts...)
 	return p
 }
 
 // DialAddr defines a string variable with specified name, usage string validated as a
 // dial address (host:port).
 // The return value is the address of a string variable that stores the value of the variable.
-func (v *VarSet) DialAddr(name, usage string) *string {
+func (v *VarSet) DialAddr(name, usage string, opts ...Option) *string {
 	p := new(string)
 	v.Var(checkedValue{
-		fn:    isDialAddr,
-		Value: newStringValue("", p),
-	}, name, usage)
+		fn:         isDialAddr,
+		constraint: "dial address (host:port)",
+		example:    "localhost:8080",
+		Value:      newStringValue("", p),
+	}, name, usage, opts...)
+	return p
+}
+
+// Hostname defines a string variable with specified name, usage string validated as an
+// RFC 1123 hostname (without port).
+// The return value is the address of a string variable that stores the value of the variable.
+func (v *VarSet) Hostname(name, usage string, opts ...Option) *string {
+	p := new(string)
+	v.Var(checkedValue{
+		fn:         isHostname,
+		schema:     map[string]interface{}{"format": "hostname"},
+		constraint: "hostname",
+		example:    "example.com",
+		Value:      newStringValue("", p),
+	}, name, usage, opts...)
+	return p
+}
+
+// LanguageTag defines a string variable with specified name, usage string validated as a
+// BCP 47 language tag (i.e. "en-AU").  Only the syntax of the tag is validated.
+// The return value is the address of a string variable that stores the value of the variable.
+func (v *VarSet) LanguageTag(name, usage string, opts ...Option) *string {
+	p := new(string)
+	v.Var(checkedValue{
+		fn:         isLanguageTag,
+		constraint: "BCP 47 language tag",
+		example:    "en-AU",
+		Value:      newStringValue("", p),
+	}, name, usage, opts...)
 	return p
 }
 
 // Path defines a string variable with specified name, usage string validated as a local path.
 // The return value is the address of a string variable that stores the value of the variable.
-func (v *VarSet) Path(name, usage string) *string {
+func (v *VarSet) Path(name, usage string, opts ...Option) *string {
 	p := new(string)
 	v.Var(checkedValue{
-		fn:    isPath,
-		Value: newStringValue("", p),
-	}, name, usage)
+		fn:         isPath,
+		constraint: "existing path",
+		example:    "/path/to/file",
+		Value:      newStringValue("", p),
+	}, name, usage, opts...)
+	return p
+}
+
+// File defines a string variable with specified name, usage string validated as a path
+// to an existing regular file.
+// The return value is the address of a string variable that stores the value of the variable.
+func (v *VarSet) File(name, usage string, opts ...Option) *string {
+	p := new(string)
+	v.Var(checkedValue{
+		fn:         isFile,
+		constraint: "existing regular file",
+		example:    "/path/to/file",
+		Value:      newStringValue("", p),
+	}, name, usage, opts...)
+	return p
+}
+
+// Dir defines a string variable with specified name, usage string validated as a path
+// to an existing directory.
+// The return value is the address of a string variable that stores the value of the variable.
+func (v *VarSet) Dir(name, usage string, opts ...Option) *string {
+	p := new(string)
+	v.Var(checkedValue{
+		fn:         isDir,
+		constraint: "existing directory",
+		example:    "/path/to/dir",
+		Value:      newStringValue("", p),
+	}, name, usage, opts...)
+	return p
+}
+
+// DirCreate defines a string variable with specified name, usage string validated as a path
+// to a directory.  If the directory does not exist then it is created (along with any
+// necessary parents) with permissions perm when the variable is parsed.
+// The return value is the address of a string variable that stores the value of the variable.
+func (v *VarSet) DirCreate(name, usage string, perm os.FileMode, opts ...Option) *string {
+	p := new(string)
+	v.Var(checkedValue{
+		fn:         makeDir(perm),
+		constraint: "directory (created if missing)",
+		example:    "/path/to/dir",
+		Value:      newStringValue("", p),
+	}, name, usage, opts...)
 	return p
 }
 
 // Errors is returned from Parse.
 type Errors []error
 
-// Error implements error.
+// Error implements error.  The message of each (non-nil) error is given on a
+// separate line.
 func (me Errors) Error() string {
-	n := 0
-	msg := ""
+	var msgs []string
 	for _, e := range me {
 		if e != nil {
-			if n == 0 {
-				msg = e.Error()
-			}
-			n++
+			msgs = append(msgs, e.Error())
 		}
 	}
-
-	switch n {
-	case 0:
+	if len(msgs) == 0 {
 		return "(0 errors)"
-	case 1:
-		return msg
-	case 2:
-		return fmt.Sprintf("%v (and 1 other error)", msg)
 	}
-	return fmt.Sprintf("%v (and %d other errors)", msg, n)
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors, so that errors.Is and errors.As can find them.
+func (me Errors) Unwrap() []error {
+	return []error(me)
 }
 
 // Getter defines the Get method.
@@ -263,24 +870,233 @@ type Getter interface {
 	Get(string) (string, bool)
 }
 
+// MapGetter is a Getter which retrieves variables from a map, for parsing
+// in tests or from sources other than the process environment:
+//
+//	err := vs.Parse(env.MapGetter{"LISTEN": ":8080"})
+type MapGetter map[string]string
+
+// Get implements Getter.
+func (g MapGetter) Get(x string) (string, bool) {
+	z, ok := g[x]
+	return z, ok
+}
+
 type osLookup struct{}
 
 func (osLookup) Get(x string) (string, bool) { return os.LookupEnv(x) }
 
+// OS returns a Getter which retrieves variables from the process environment.
+func OS() Getter {
+	return osLookup{}
+}
+
 // Parse parses variables from the environment provided by
-// the Getter.
-func (v *VarSet) Parse(g Getter) error {
+// the Getter.  By default every variable must be set (unless it is Optional
+// or has a Default) and all errors are collected: this can be changed
+// with ParseOptions.
+func (v *VarSet) Parse(g Getter, opts ...ParseOption) error {
+	var c parseConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	err := v.parse(g, c)
+	v.emitEvents(c, err)
+	return err
+}
+
+// emitEvents calls the OnEvent function of c, if any, with the events for err.
+func (v *VarSet) emitEvents(c parseConfig, err error) {
+	if err != nil && c.onEvent != nil {
+		for _, e := range v.Events(err) {
+			c.onEvent(e)
+		}
+	}
+}
+
+// parse parses variables from the environment provided by the Getter.
+// If c.reload is true then Static variables are skipped.
+func (v *VarSet) parse(g Getter, c parseConfig) error {
+	if c.emptyAsUnset {
+		g = nonEmptyGetter{g}
+	}
+	if c.discriminator != "" {
+		if z, ok := g.Get(c.discriminator); ok && z != "" {
+			g = suffixGetter{g, c.discriminator, suffixName(z)}
+		}
+	}
+	if c.stdin == nil {
+		c.stdin = &lineReader{r: os.Stdin}
+	}
+
 	var errs []error
+	fail := func(err error) bool {
+		errs = append(errs, err)
+		return c.failFast
+	}
 
 	for _, x := range v.vars {
-		z, ok := g.Get(x.Name)
+		if c.reload && x.Scope == Static {
+			continue
+		}
+
+		z, source, ok := x.lookup(g, c.legacy)
+		if !ok && x.def != nil {
+			z, ok = x.def(), true
+		}
+
+		// failVar handles a failure of x according to its criticality, and reports
+		// whether parsing should stop.
+		failVar := func(err error) bool {
+			if x.criticality == Critical {
+				return fail(err)
+			}
+			useDefault := source != "" && x.def != nil
+			if useDefault {
+				x.set(x.def(), func(string) {})
+				x.raw, x.source = "", ""
+			}
+			if x.criticality == Important {
+				if useDefault {
+					c.warn(fmt.Sprintf("%v (using default)", err))
+				} else {
+					c.warn(err.Error())
+				}
+			}
+			return false
+		}
 		if !ok {
-			errs = append(errs, fmt.Errorf("missing env %v", x.Name))
+			if x.optional || c.ignoreMissing {
+				continue
+			}
+			if failVar(&MissingError{Name: x.Name}) {
+				return Errors(errs)
+			}
 			continue
 		}
 
-		if err := x.Value.Set(z); err != nil {
-			errs = append(errs, fmt.Errorf("could not set env %v: %v", x.Name, err))
+		value := z
+		if x.stdin && z == "-" {
+			var err error
+			if value, err = c.stdin.readLine(); err != nil {
+				if failVar(&ParseError{Name: x.Name, Value: z, Err: fmt.Errorf("could not read from stdin: %v", err)}) {
+					return Errors(errs)
+				}
+				continue
+			}
+		}
+
+		warn := func(msg string) { c.warn(fmt.Sprintf("env %v: %v", x.Name, msg)) }
+		if err := x.set(value, warn); err != nil {
+			if failVar(&ParseError{Name: x.Name, Value: value, Err: err}) {
+				return Errors(errs)
+			}
+			continue
+		}
+		x.raw = z
+		x.source = source
+
+		if x.isDeprecated(source) || source != "" && source != x.Name && !x.isFallback(source) {
+			c.warn(fmt.Sprintf("env %v is deprecated, use %v", source, x.Name))
+		}
+	}
+
+	// Sub-sets use the Getter as already wrapped above.
+	sc := c
+	sc.emptyAsUnset, sc.discriminator = false, ""
+	for _, s := range v.subs {
+		if err := s.parse(g, sc); err != nil {
+			errs = append(errs, err.(Errors)...)
+			if c.failFast {
+				return Errors(errs)
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		for _, fn := range v.checks {
+			if err := fn(); err != nil {
+				if fail(err) {
+					return Errors(errs)
+				}
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return Errors(errs)
+}
+
+// changedStatic returns the names of Static variables whose values in the environment
+// provided by the Getter differ from those last parsed.
+func (v *VarSet) changedStatic(g Getter) []string {
+	var names []string
+	v.Visit(func(x *Var) {
+		if x.Scope != Static {
+			return
+		}
+		if z, _, ok := x.lookup(g, nil); ok && z != x.raw {
+			names = append(names, x.Name)
+		}
+	})
+	return names
+}
+
+// SetErrors contains the errors from parsing a single VarSet in ParseAll.
+type SetErrors struct {
+	Name   string // name of the VarSet
+	Errors Errors // errors returned from parsing the VarSet
+}
+
+// Error implements error.
+func (e *SetErrors) Error() string {
+	if e.Name == "" {
+		return e.Errors.Error()
+	}
+	return fmt.Sprintf("%v: %v", e.Name, e.Errors)
+}
+
+// Unwrap returns the errors from parsing the VarSet.
+func (e *SetErrors) Unwrap() error {
+	return e.Errors
+}
+
+// ParseAll parses the variables in each of the variable sets from the
+// environment provided by the Getter.
+//
+// Every set is parsed, even if an earlier set fails.  Errors are grouped by set:
+// the returned Errors contains a *SetErrors for each set which failed to parse.
+// Variables with the same name defined in more than one set are also reported
+// as errors, as each set would otherwise interpret the same value independently,
+// as are variables in a namespace reserved by another set (see ReserveNamespace).
+func ParseAll(g Getter, sets ...*VarSet) error {
+	return parseAll(g, parseConfig{}, sets)
+}
+
+func parseAll(g Getter, c parseConfig, sets []*VarSet) error {
+	var errs []error
+
+	defined := make(map[string]*VarSet)
+	for _, vs := range sets {
+		vs.Visit(func(x *Var) {
+			if other, ok := defined[x.Name]; ok && other != vs {
+				errs = append(errs, fmt.Errorf("env %v defined in sets %q and %q", x.Name, other.name, vs.name))
+				return
+			}
+			defined[x.Name] = vs
+		})
+		errs = append(errs, vs.checkNamespaces()...)
+	}
+
+	for _, vs := range sets {
+		if err := vs.parse(g, c); err != nil {
+			es, ok := err.(Errors)
+			if !ok {
+				es = Errors{err}
+			}
+			errs = append(errs, &SetErrors{Name: vs.name, Errors: es})
 		}
 	}
 
@@ -302,53 +1118,142 @@ var CmdName = func() string {
 
 // String defines a string variable with specified name, usage string and validation checks.
 // The return value is the address of a string variable that stores the value of the variable.
-func String(name, usage string) *string {
-	return CmdVar.String(name, usage)
+func String(name, usage string, opts ...Option) *string {
+	return CmdVar.String(name, usage, opts...)
 }
 
 // StringRequired defines a required string variable with specified name and usage string..
 // The return value is the address of a string variable that stores the value of the variable.
-func StringRequired(name, usage string) *string {
-	return CmdVar.StringRequired(name, usage)
+func StringRequired(name, usage string, opts ...Option) *string {
+	return CmdVar.StringRequired(name, usage, opts...)
 }
 
 // BindAddr defines a string variable with specified name, usage string validated as a
 // bind address (host:port).
 // The return value is the address of a string variable that stores the value of the variable.
-func BindAddr(name, usage string) *string {
-	return CmdVar.BindAddr(name, usage)
+func BindAddr(name, usage string, opts ...Option) *string {
+	return CmdVar.BindAddr(name, usage, opts...)
 }
 
 // DialAddr defines a string variable with specified name, usage string validated as a
 // dial address (host:port).
 // The return value is the address of a string variable that stores the value of the variable.
-func DialAddr(name, usage string) *string {
-	return CmdVar.DialAddr(name, usage)
+func DialAddr(name, usage string, opts ...Option) *string {
+	return CmdVar.DialAddr(name, usage, opts...)
+}
+
+// Hostname defines a string variable with specified name, usage string validated as an
+// RFC 1123 hostname (without port).
+// The return value is the address of a string variable that stores the value of the variable.
+func Hostname(name, usage string, opts ...Option) *string {
+	return CmdVar.Hostname(name, usage, opts...)
+}
+
+// LanguageTag defines a string variable with specified name, usage string validated as a
+// BCP 47 language tag (i.e. "en-AU").  Only the syntax of the tag is validated.
+// The return value is the address of a string variable that stores the value of the variable.
+func LanguageTag(name, usage string, opts ...Option) *string {
+	return CmdVar.LanguageTag(name, usage, opts...)
 }
 
 // Path defines a string variable with specified name, usage string validated as a
 // local path.
 // The return value is the address of a string variable that stores the value of the variable.
-func Path(name, usage string) *string {
-	return CmdVar.Path(name, usage)
+func Path(name, usage string, opts ...Option) *string {
+	return CmdVar.Path(name, usage, opts...)
+}
+
+// File defines a string variable with specified name, usage string validated as a
+// path to an existing regular file.
+// The return value is the address of a string variable that stores the value of the variable.
+func File(name, usage string, opts ...Option) *string {
+	return CmdVar.File(name, usage, opts...)
+}
+
+// Dir defines a string variable with specified name, usage string validated as a
+// path to an existing directory.
+// The return value is the address of a string variable that stores the value of the variable.
+func Dir(name, usage string, opts ...Option) *string {
+	return CmdVar.Dir(name, usage, opts...)
+}
+
+// DirCreate defines a string variable with specified name, usage string validated as a
+// path to a directory, which is created with permissions perm if it does not exist.
+// The return value is the address of a string variable that stores the value of the variable.
+func DirCreate(name, usage string, perm os.FileMode, opts ...Option) *string {
+	return CmdVar.DirCreate(name, usage, perm, opts...)
 }
 
 // Int defines an int variable with specified name and usage string.
 // The return value is the address of an int variable that stores the value of the variable.
-func Int(name string, usage string) *int {
-	return CmdVar.Int(name, usage)
+func Int(name string, usage string, opts ...Option) *int {
+	return CmdVar.Int(name, usage, opts...)
 }
 
 // Bool defines a bool variable with specified name and usage string.
 // The return value is the address of a bool variable that stores the value of the variable.
-func Bool(name string, usage string) *bool {
-	return CmdVar.Bool(name, usage)
+func Bool(name string, usage string, opts ...Option) *bool {
+	return CmdVar.Bool(name, usage, opts...)
 }
 
 // Duration defines a time.Duration variable with specified name, usage string and validation checks.
 // The return value is the address of a time.Duration variable that stores the value of the variable.
-func Duration(name string, usage string) *time.Duration {
-	return CmdVar.Duration(name, usage)
+func Duration(name string, usage string, opts ...Option) *time.Duration {
+	return CmdVar.Duration(name, usage, opts...)
+}
+
+// Func defines a variable with specified name and usage string.  Each time the variable
+// is parsed fn is called with its value, and any error returned by fn is reported by Parse.
+func Func(name, usage string, fn func(string) error, opts ...Option) {
+	CmdVar.Func(name, usage, fn, opts...)
+}
+
+// Text defines a variable with specified name and usage string which is parsed by
+// calling p.UnmarshalText (see VarSet.Text).
+func Text(name, usage string, p encoding.TextUnmarshaler, opts ...Option) {
+	CmdVar.Text(name, usage, p, opts...)
+}
+
+// Location defines a *time.Location variable with specified name and usage string.
+// The value is loaded with time.LoadLocation from an IANA time zone name.
+// The return value is the address of a *time.Location variable that stores the value of the variable.
+func Location(name, usage string, opts ...Option) **time.Location {
+	return CmdVar.Location(name, usage, opts...)
+}
+
+// BigInt defines a *big.Int variable with specified name and usage string, parsed in the
+// given base (0 to detect the base from the value's prefix, see VarSet.BigInt).
+// The return value is the address of a *big.Int variable that stores the value of the variable.
+func BigInt(name, usage string, base int, opts ...Option) **big.Int {
+	return CmdVar.BigInt(name, usage, base, opts...)
+}
+
+// FileMode defines an os.FileMode variable with specified name and usage string.  Values
+// are given in octal (i.e. "0640").
+// The return value is the address of an os.FileMode variable that stores the value of the variable.
+func FileMode(name, usage string, opts ...Option) *os.FileMode {
+	return CmdVar.FileMode(name, usage, opts...)
+}
+
+// MAC defines a net.HardwareAddr variable with specified name and usage string, parsed
+// with net.ParseMAC.
+// The return value is the address of a net.HardwareAddr variable that stores the value of the variable.
+func MAC(name, usage string, opts ...Option) *net.HardwareAddr {
+	return CmdVar.MAC(name, usage, opts...)
+}
+
+// Percent defines a float64 variable with specified name and usage string holding a
+// percentage in the range [0, 100], given as "25%" or "25".
+// The return value is the address of a float64 variable that stores the value of the variable.
+func Percent(name, usage string, opts ...Option) *float64 {
+	return CmdVar.Percent(name, usage, opts...)
+}
+
+// PercentFraction defines a float64 variable with specified name and usage string holding a
+// percentage in the range [0, 100], given as "25%" or "0.25".
+// The return value is the address of a float64 variable that stores the value of the variable.
+func PercentFraction(name, usage string, opts ...Option) *float64 {
+	return CmdVar.PercentFraction(name, usage, opts...)
 }
 
 // Visit visits the variables in the order in which they were defined, calling fn for each.
@@ -357,6 +1262,6 @@ func Visit(fn func(*Var)) {
 }
 
 // Parse parses variables from the process environment.
-func Parse() error {
-	return CmdVar.Parse(osLookup{})
+func Parse(opts ...ParseOption) error {
+	return CmdVar.Parse(osLookup{}, opts...)
 }
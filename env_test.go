@@ -1,6 +1,10 @@
 package env_test
 
 import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
 
 	"code.sajari.com/env"
@@ -48,3 +52,621 @@ func TestAll(t *testing.T) {
 		}
 	})
 }
+
+func TestDescribe(t *testing.T) {
+	vs := env.NewVarSet("")
+	vs.String("STRING", "string test")
+	vs.Int("INT", "int test")
+	vs.BindAddr("LISTEN", "bindaddr test")
+
+	want := map[string]string{
+		"STRING": "",
+		"INT":    "integer",
+		"LISTEN": "bind address (host:port)",
+	}
+
+	vs.Visit(func(v *env.Var) {
+		constraint, example := env.Describe(v.Value)
+		if constraint != want[v.Name] {
+			t.Errorf("env.Describe(%v) constraint = %q, expected %q", v.Name, constraint, want[v.Name])
+		}
+		if example != "" {
+			if err := v.Value.Set(example); err != nil {
+				t.Errorf("env.Describe(%v) example %q is invalid: %v", v.Name, example, err)
+			}
+		}
+	})
+}
+
+func TestParseAll(t *testing.T) {
+	app := env.NewVarSet("app")
+	app.Int("WORKERS", "workers test")
+	app.String("NAME", "name test")
+
+	lib := env.NewVarSet("lib")
+	lib.Bool("ENABLED", "enabled test")
+
+	tg := testGetter{
+		"APP_WORKERS": "4",
+		"APP_NAME":    "name",
+		"LIB_ENABLED": "true",
+	}
+	if err := env.ParseAll(tg, app, lib); err != nil {
+		t.Errorf("unexpected error from ParseAll: %v", err)
+	}
+
+	tg = testGetter{
+		"APP_WORKERS": "a",
+		"APP_NAME":    "name",
+	}
+	err := env.ParseAll(tg, app, lib)
+	es, ok := err.(env.Errors)
+	if !ok || len(es) != 2 {
+		t.Fatalf("env.ParseAll() = %v, expected 2 errors", err)
+	}
+	for i, name := range []string{"app", "lib"} {
+		se, ok := es[i].(*env.SetErrors)
+		if !ok {
+			t.Errorf("es[%d] = %T, expected *env.SetErrors", i, es[i])
+			continue
+		}
+		if se.Name != name || len(se.Errors) != 1 {
+			t.Errorf("es[%d] = %v, expected one error from set %q", i, se, name)
+		}
+	}
+}
+
+func TestParseAllDuplicate(t *testing.T) {
+	a := env.NewVarSet("")
+	a.String("NAME", "name test")
+
+	b := env.NewVarSet("")
+	b.String("NAME", "name test")
+
+	if err := env.ParseAll(testGetter{"NAME": "name"}, a, b); err == nil {
+		t.Errorf("expected error for duplicate var")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	vs := env.NewVarSet("")
+	min := vs.Int("MIN", "min test")
+	max := vs.Int("MAX", "max test")
+
+	called := 0
+	vs.Validate(func() error {
+		called++
+		if *min > *max {
+			return errors.New("min > max")
+		}
+		return nil
+	})
+
+	if err := vs.Parse(testGetter{"MIN": "1", "MAX": "2"}); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if err := vs.Parse(testGetter{"MIN": "2", "MAX": "1"}); err == nil {
+		t.Errorf("expected error from failed check")
+	}
+	if err := vs.Parse(testGetter{"MIN": "a", "MAX": "1"}); err == nil {
+		t.Errorf("expected error from invalid var")
+	}
+	if called != 2 {
+		t.Errorf("check called %d times, expected 2", called)
+	}
+}
+
+func TestFunc(t *testing.T) {
+	vs := env.NewVarSet("")
+
+	var hosts []string
+	vs.Func("HOSTS", "func test", func(x string) error {
+		if x == "" {
+			return errors.New("empty")
+		}
+		hosts = strings.Split(x, ",")
+		return nil
+	})
+
+	if err := vs.Parse(testGetter{"HOSTS": "a,b"}); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if !reflect.DeepEqual(hosts, []string{"a", "b"}) {
+		t.Errorf("hosts = %q, expected %q", hosts, []string{"a", "b"})
+	}
+	vs.Visit(func(v *env.Var) {
+		if s := v.Value.String(); s != "a,b" {
+			t.Errorf("v.Value.String() = %q, expected %q", s, "a,b")
+		}
+	})
+
+	if err := vs.Parse(testGetter{"HOSTS": ""}); err == nil {
+		t.Errorf("expected error from Parse")
+	}
+}
+
+func TestOptional(t *testing.T) {
+	vs := env.NewVarSet("")
+	name := vs.String("NAME", "optional test", env.Optional())
+	workers := vs.Int("WORKERS", "optional test", env.Optional())
+
+	if err := vs.Parse(testGetter{}); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *name != "" || *workers != 0 {
+		t.Errorf("got (%q, %d), expected zero values", *name, *workers)
+	}
+
+	if err := vs.Parse(testGetter{"NAME": "a", "WORKERS": "2"}); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *name != "a" || *workers != 2 {
+		t.Errorf("got (%q, %d), expected (\"a\", 2)", *name, *workers)
+	}
+
+	if err := vs.Parse(testGetter{"WORKERS": "b"}); err == nil {
+		t.Errorf("expected error from invalid optional var")
+	}
+}
+
+func TestDefault(t *testing.T) {
+	vs := env.NewVarSet("")
+	name := vs.String("NAME", "default test", env.Default("a"))
+
+	calls := 0
+	workers := vs.Int("WORKERS", "default func test", env.DefaultFunc(func() string {
+		calls++
+		return "4"
+	}))
+
+	if err := vs.Parse(testGetter{}); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *name != "a" || *workers != 4 {
+		t.Errorf("got (%q, %d), expected (\"a\", 4)", *name, *workers)
+	}
+
+	if err := vs.Parse(testGetter{"NAME": "b", "WORKERS": "2"}); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *name != "b" || *workers != 2 {
+		t.Errorf("got (%q, %d), expected (\"b\", 2)", *name, *workers)
+	}
+	if calls != 1 {
+		t.Errorf("default func called %d times, expected 1", calls)
+	}
+
+	vs = env.NewVarSet("")
+	vs.Int("WORKERS", "invalid default test", env.Default("x"))
+	if err := vs.Parse(testGetter{}); err == nil {
+		t.Errorf("expected error from invalid default")
+	}
+}
+
+func TestFallbackTo(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	addr := vs.String("ADDR", "fallback test", env.FallbackTo("LEGACY_ADDR"), env.FallbackTo("OLD_ADDR"))
+
+	tests := []struct {
+		env     testGetter
+		out     string
+		wantErr bool
+	}{
+		{testGetter{"SVC_ADDR": "a", "LEGACY_ADDR": "b", "OLD_ADDR": "c"}, "a", false},
+		{testGetter{"LEGACY_ADDR": "b", "OLD_ADDR": "c"}, "b", false},
+		{testGetter{"OLD_ADDR": "c"}, "c", false},
+		{testGetter{"ADDR": "d"}, "c", true},
+	}
+
+	for _, tt := range tests {
+		if err := vs.Parse(tt.env); (err != nil) != tt.wantErr {
+			t.Errorf("vs.Parse(%v) = %v, wantErr %v", tt.env, err, tt.wantErr)
+		}
+		if *addr != tt.out {
+			t.Errorf("vs.Parse(%v): got %q, expected %q", tt.env, *addr, tt.out)
+		}
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	newVarSet := func() (*env.VarSet, *string, *int) {
+		vs := env.NewVarSet("")
+		name := vs.String("NAME", "parse option test", env.Default("a"))
+		workers := vs.Int("WORKERS", "parse option test")
+		return vs, name, workers
+	}
+
+	vs, _, _ := newVarSet()
+	if err := vs.Parse(testGetter{}); err == nil {
+		t.Errorf("expected error from missing var")
+	}
+
+	vs, _, workers := newVarSet()
+	if err := vs.Parse(testGetter{}, env.IgnoreMissing()); err != nil {
+		t.Errorf("unexpected error from Parse with IgnoreMissing: %v", err)
+	}
+	if *workers != 0 {
+		t.Errorf("got %d, expected zero value", *workers)
+	}
+
+	vs, _, _ = newVarSet()
+	vs.Bool("DEBUG", "parse option test")
+	if err := vs.Parse(testGetter{}); len(err.(env.Errors)) != 2 {
+		t.Errorf("got %v, expected 2 errors", err)
+	}
+	if err := vs.Parse(testGetter{}, env.FailFast()); len(err.(env.Errors)) != 1 {
+		t.Errorf("got %v, expected 1 error with FailFast", err)
+	}
+
+	vs, name, _ := newVarSet()
+	if err := vs.Parse(testGetter{"NAME": "", "WORKERS": "1"}); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *name != "" {
+		t.Errorf("got %q, expected empty string", *name)
+	}
+	if err := vs.Parse(testGetter{"NAME": "", "WORKERS": "1"}, env.TreatEmptyAsUnset()); err != nil {
+		t.Errorf("unexpected error from Parse with TreatEmptyAsUnset: %v", err)
+	}
+	if *name != "a" {
+		t.Errorf("got %q, expected default \"a\"", *name)
+	}
+	if err := vs.Parse(testGetter{"NAME": "b", "WORKERS": ""}, env.TreatEmptyAsUnset()); err == nil {
+		t.Errorf("expected error from empty var with TreatEmptyAsUnset")
+	}
+}
+
+func TestOverrideBy(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	workers := vs.Int("WORKERS", "override test")
+	name := vs.String("NAME", "override test")
+
+	tests := []struct {
+		env     testGetter
+		workers int
+		name    string
+	}{
+		{testGetter{"SVC_WORKERS": "4", "SVC_NAME": "a", "SVC_WORKERS_US_EAST1": "8"}, 4, "a"},
+		{testGetter{"REGION": "us-east1", "SVC_WORKERS": "4", "SVC_NAME": "a", "SVC_WORKERS_US_EAST1": "8"}, 8, "a"},
+		{testGetter{"REGION": "eu-west1", "SVC_WORKERS": "4", "SVC_NAME": "a", "SVC_WORKERS_US_EAST1": "8"}, 4, "a"},
+		{testGetter{"REGION": "", "SVC_WORKERS": "4", "SVC_NAME": "a", "SVC_WORKERS_": "8"}, 4, "a"},
+	}
+
+	for _, tt := range tests {
+		if err := vs.Parse(tt.env, env.OverrideBy("REGION")); err != nil {
+			t.Errorf("vs.Parse(%v) = %v, expected nil", tt.env, err)
+		}
+		if *workers != tt.workers || *name != tt.name {
+			t.Errorf("vs.Parse(%v): got (%d, %q), expected (%d, %q)", tt.env, *workers, *name, tt.workers, tt.name)
+		}
+	}
+}
+
+func TestAlias(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	vs.String("ADDR", "alias test", env.Alias("LISTEN"), env.Alias("BIND"))
+
+	var x *env.Var
+	vs.Visit(func(v *env.Var) { x = v })
+
+	tests := []struct {
+		env     testGetter
+		out     string
+		source  string
+		wantErr bool
+	}{
+		{testGetter{"SVC_ADDR": "a", "SVC_LISTEN": "b", "SVC_BIND": "c"}, "a", "SVC_ADDR", false},
+		{testGetter{"SVC_LISTEN": "b", "SVC_BIND": "c"}, "b", "SVC_LISTEN", false},
+		{testGetter{"SVC_BIND": "c"}, "c", "SVC_BIND", false},
+		{testGetter{"LISTEN": "d"}, "c", "SVC_BIND", true},
+	}
+
+	for _, tt := range tests {
+		if err := vs.Parse(tt.env); (err != nil) != tt.wantErr {
+			t.Errorf("vs.Parse(%v) = %v, wantErr %v", tt.env, err, tt.wantErr)
+		}
+		if got := x.Value.String(); got != tt.out {
+			t.Errorf("vs.Parse(%v): got %q, expected %q", tt.env, got, tt.out)
+		}
+		if got := x.Source(); got != tt.source {
+			t.Errorf("vs.Parse(%v): got source %q, expected %q", tt.env, got, tt.source)
+		}
+	}
+}
+
+func TestAliasReused(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	opts := []env.Option{env.Alias("OLD"), env.Optional()}
+	a := vs.String("A", "alias test", opts...)
+	b := vs.String("B", "alias test", opts...)
+
+	if err := vs.Parse(testGetter{"SVC_OLD": "x"}); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *a != "x" || *b != "x" {
+		t.Errorf("got (%q, %q), expected (\"x\", \"x\")", *a, *b)
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	addr := vs.String("ADDR", "deprecated test", env.Deprecated("LISTEN"))
+
+	var warnings []string
+	onWarning := env.OnWarning(func(msg string) { warnings = append(warnings, msg) })
+
+	if err := vs.Parse(testGetter{"SVC_ADDR": "a", "SVC_LISTEN": "b"}, onWarning); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *addr != "a" || len(warnings) != 0 {
+		t.Errorf("got (%q, %q), expected (\"a\", no warnings)", *addr, warnings)
+	}
+
+	if err := vs.Parse(testGetter{"SVC_LISTEN": "b"}, onWarning); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	expected := []string{"env SVC_LISTEN is deprecated, use SVC_ADDR"}
+	if *addr != "b" || !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("got (%q, %q), expected (\"b\", %q)", *addr, warnings, expected)
+	}
+}
+
+func TestDeprecatedReused(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	opts := []env.Option{env.Deprecated("OLD"), env.Optional()}
+	a := vs.String("A", "deprecated test", opts...)
+	b := vs.String("B", "deprecated test", opts...)
+
+	var warnings []string
+	onWarning := env.OnWarning(func(msg string) { warnings = append(warnings, msg) })
+	if err := vs.Parse(testGetter{"SVC_OLD": "x"}, onWarning); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	expected := []string{"env SVC_OLD is deprecated, use SVC_A", "env SVC_OLD is deprecated, use SVC_B"}
+	if *a != "x" || *b != "x" || !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("got (%q, %q, %q), expected (\"x\", \"x\", %q)", *a, *b, warnings, expected)
+	}
+}
+
+func TestNoPrefix(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	port := vs.Int("PORT", "no prefix test", env.NoPrefix())
+	workers := vs.Int("WORKERS", "no prefix test")
+
+	var names []string
+	vs.Visit(func(v *env.Var) { names = append(names, v.Name) })
+	if expected := []string{"PORT", "SVC_WORKERS"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("got names %q, expected %q", names, expected)
+	}
+
+	if err := vs.Parse(testGetter{"PORT": "8080", "SVC_PORT": "1", "SVC_WORKERS": "4"}); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *port != 8080 || *workers != 4 {
+		t.Errorf("got (%d, %d), expected (8080, 4)", *port, *workers)
+	}
+}
+
+func TestNoPrefixAlias(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	before := vs.String("HOME", "no prefix alias test", env.Alias("USERPROFILE"), env.NoPrefix())
+	after := vs.String("PROXY", "no prefix alias test", env.NoPrefix(), env.Alias("HTTP_PROXY"))
+
+	g := testGetter{"USERPROFILE": "/home/x", "HTTP_PROXY": "proxy", "SVC_USERPROFILE": "a", "SVC_HTTP_PROXY": "b"}
+	if err := vs.Parse(g); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *before != "/home/x" || *after != "proxy" {
+		t.Errorf("got (%q, %q), expected (\"/home/x\", \"proxy\")", *before, *after)
+	}
+}
+
+func TestSub(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	workers := vs.Int("WORKERS", "sub test")
+	db := vs.Sub("db")
+	dbAddr := db.String("ADDR", "sub test")
+	pool := db.Sub("pool")
+	size := pool.Int("SIZE", "sub test")
+
+	if got, expected := pool.Prefix(), "SVC_DB_POOL"; got != expected {
+		t.Errorf("got prefix %q, expected %q", got, expected)
+	}
+
+	var names []string
+	vs.Visit(func(v *env.Var) { names = append(names, v.Name) })
+	if expected := []string{"SVC_WORKERS", "SVC_DB_ADDR", "SVC_DB_POOL_SIZE"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("got names %q, expected %q", names, expected)
+	}
+
+	if err := vs.Parse(testGetter{"SVC_WORKERS": "4", "SVC_DB_ADDR": "db:5432", "SVC_DB_POOL_SIZE": "10"}); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *workers != 4 || *dbAddr != "db:5432" || *size != 10 {
+		t.Errorf("got (%d, %q, %d), expected (4, \"db:5432\", 10)", *workers, *dbAddr, *size)
+	}
+
+	if err := vs.Parse(testGetter{"SVC_WORKERS": "4", "SVC_DB_ADDR": "db:5432"}); err == nil {
+		t.Errorf("expected error from missing sub-set var")
+	}
+}
+
+func TestAddVarSet(t *testing.T) {
+	lib := env.NewVarSet("redis")
+	addr := lib.String("ADDR", "add var set test")
+
+	vs := env.NewVarSet("svc")
+	workers := vs.Int("WORKERS", "add var set test")
+	if err := vs.AddVarSet(lib); err != nil {
+		t.Fatalf("unexpected error from AddVarSet: %v", err)
+	}
+
+	if err := vs.Parse(testGetter{"SVC_WORKERS": "4", "REDIS_ADDR": "redis:6379"}); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *workers != 4 || *addr != "redis:6379" {
+		t.Errorf("got (%d, %q), expected (4, \"redis:6379\")", *workers, *addr)
+	}
+
+	if err := env.NewVarSet("other").AddVarSet(lib); err == nil {
+		t.Errorf("expected error adding set twice")
+	}
+	if err := lib.AddVarSet(vs); err == nil {
+		t.Errorf("expected error adding set to itself")
+	}
+
+	dup := env.NewVarSet("svc")
+	dup.Int("WORKERS", "add var set test")
+	if err := vs.AddVarSet(dup); err == nil {
+		t.Errorf("expected error from duplicate var")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	errInvalid := errors.New("invalid")
+
+	vs := env.NewVarSet("")
+	vs.String("NAME", "error test")
+	vs.Func("HOSTS", "error test", func(string) error { return errInvalid })
+
+	err := vs.Parse(testGetter{"HOSTS": "x"})
+	es, ok := err.(env.Errors)
+	if !ok || len(es) != 2 {
+		t.Fatalf("got %v, expected 2 errors", err)
+	}
+
+	if e, ok := es[0].(*env.MissingError); !ok || e.Name != "NAME" {
+		t.Errorf("got %#v, expected *env.MissingError for NAME", es[0])
+	}
+
+	e, ok := es[1].(*env.ParseError)
+	if !ok || e.Name != "HOSTS" || e.Value != "x" {
+		t.Fatalf("got %#v, expected *env.ParseError for HOSTS", es[1])
+	}
+	if !errors.Is(e, errInvalid) {
+		t.Errorf("errors.Is(%v, errInvalid) = false, expected true", e)
+	}
+	if got, expected := e.Error(), "could not set env HOSTS: invalid"; got != expected {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	vs.String("NAME", "errors test")
+	vs.Func("HOSTS", "errors test", func(string) error { return os.ErrInvalid })
+
+	err := env.ParseAll(testGetter{"SVC_HOSTS": "x"}, vs)
+	if got, expected := err.Error(), "svc: missing env SVC_NAME\ncould not set env SVC_HOSTS: invalid argument"; got != expected {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+
+	var missing *env.MissingError
+	if !errors.As(err, &missing) || missing.Name != "SVC_NAME" {
+		t.Errorf("errors.As(*env.MissingError) = %v, expected SVC_NAME", missing)
+	}
+	if !errors.Is(err, os.ErrInvalid) {
+		t.Errorf("errors.Is(os.ErrInvalid) = false, expected true")
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		t.Errorf("errors.Is(os.ErrNotExist) = true, expected false")
+	}
+}
+
+func TestMapGetter(t *testing.T) {
+	vs := env.NewVarSet("")
+	listen := vs.BindAddr("LISTEN", "map getter test")
+
+	m := map[string]string{"LISTEN": ":8080"}
+	if err := vs.Parse(env.MapGetter(m)); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *listen != ":8080" {
+		t.Errorf("got %q, expected \":8080\"", *listen)
+	}
+	if err := vs.Parse(env.MapGetter(nil)); err == nil {
+		t.Errorf("expected error from missing var")
+	}
+}
+
+func TestLegacyTable(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	addr := vs.String("ADDR", "legacy test")
+	workers := vs.Int("WORKERS", "legacy test")
+
+	var warnings []string
+	opts := []env.ParseOption{
+		env.LegacyTable(map[string]string{"OLD_SVC_ADDR": "SVC_ADDR", "SVC_THREADS": "SVC_WORKERS", "THREADS": "SVC_WORKERS"}),
+		env.OnWarning(func(msg string) { warnings = append(warnings, msg) }),
+	}
+
+	if err := vs.Parse(testGetter{"SVC_ADDR": "a", "OLD_SVC_ADDR": "b", "SVC_WORKERS": "1"}, opts...); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *addr != "a" || *workers != 1 || len(warnings) != 0 {
+		t.Errorf("got (%q, %d, %q), expected (\"a\", 1, no warnings)", *addr, *workers, warnings)
+	}
+
+	if err := vs.Parse(testGetter{"OLD_SVC_ADDR": "b", "SVC_THREADS": "2", "THREADS": "3"}, opts...); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	expected := []string{"env OLD_SVC_ADDR is deprecated, use SVC_ADDR", "env SVC_THREADS is deprecated, use SVC_WORKERS"}
+	if *addr != "b" || *workers != 2 || !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("got (%q, %d, %q), expected (\"b\", 2, %q)", *addr, *workers, warnings, expected)
+	}
+}
+
+func TestLegacyOrder(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	vs.String("ADDR", "legacy test", env.Alias("LISTEN"))
+	var x *env.Var
+	vs.Visit(func(v *env.Var) { x = v })
+	legacy := env.LegacyTable(map[string]string{"OLD_ADDR": "SVC_ADDR"})
+	quiet := env.OnWarning(func(string) {})
+
+	if err := vs.Parse(testGetter{"SVC_LISTEN": "a", "OLD_ADDR": "b"}, legacy, quiet); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if got := x.Value.String(); got != "a" || x.Source() != "SVC_LISTEN" {
+		t.Errorf("got (%q, source %q), expected alias (\"a\", source \"SVC_LISTEN\")", got, x.Source())
+	}
+
+	if err := vs.Parse(testGetter{"OLD_ADDR": "b"}, legacy, quiet); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if got := x.Value.String(); got != "b" || x.Source() != "OLD_ADDR" {
+		t.Errorf("got (%q, source %q), expected legacy (\"b\", source \"OLD_ADDR\")", got, x.Source())
+	}
+}
+
+func TestCriticality(t *testing.T) {
+	vs := env.NewVarSet("")
+	listen := vs.BindAddr("LISTEN", "criticality test")
+	workers := vs.Int("WORKERS", "criticality test", env.WithCriticality(env.Important), env.Default("4"))
+	region := vs.String("REGION", "criticality test", env.WithCriticality(env.Important))
+	batch := vs.Int("BATCH", "criticality test", env.WithCriticality(env.Minor), env.Default("10"))
+	cache := vs.Int("CACHE", "criticality test", env.WithCriticality(env.Minor))
+
+	var warnings []string
+	onWarning := env.OnWarning(func(msg string) { warnings = append(warnings, msg) })
+
+	in := testGetter{"LISTEN": ":80", "WORKERS": "x", "BATCH": "y", "CACHE": "z"}
+	if err := vs.Parse(in, onWarning); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *listen != ":80" || *workers != 4 || *region != "" || *batch != 10 || *cache != 0 {
+		t.Errorf("got (%q, %d, %q, %d, %d), expected (\":80\", 4, \"\", 10, 0)", *listen, *workers, *region, *batch, *cache)
+	}
+	expected := []string{
+		`could not set env WORKERS: parsing "x": invalid syntax (using default)`,
+		"missing env REGION",
+	}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("got warnings %q, expected %q", warnings, expected)
+	}
+
+	delete(in, "LISTEN")
+	if err := vs.Parse(in, onWarning); err == nil {
+		t.Errorf("expected error from missing critical var")
+	}
+}
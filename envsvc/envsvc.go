@@ -1,7 +1,8 @@
 // Package envsvc provides convenience methods for using env with
 // services.
 //
-// It exposes these variables via HTTP at /debug/env in JSON format.
+// It exposes these variables via HTTP at /debug/env in JSON, HTML or
+// Prometheus text format.
 package envsvc
 
 import (
@@ -21,6 +22,8 @@ import (
 // -env-dump-yaml: skips parsing steps and write each env.Var to stderr in YAML format, calls
 // os.Exit(0) when done.
 // -env-check: calls os.Exit(0) if env.Parse() succeeds without error.
+//
+// The values of secret variables (see env.Secret) are redacted from all output.
 func Parse() {
 	envCheck := flag.Bool("env-check", false, "check env variables")
 	envDump := flag.Bool("env-dump", false, "dump env variables")
@@ -39,7 +42,7 @@ func Parse() {
 				fmt.Fprintf(outWriter, ",\n")
 			}
 			first = false
-			fmt.Fprintf(outWriter, "    %q: %q", v.Name, os.Getenv(v.Name))
+			fmt.Fprintf(outWriter, "    %q: %q", v.Name, environ(v))
 		})
 		fmt.Fprintf(outWriter, "\n}\n")
 		os.Exit(0)
@@ -47,7 +50,7 @@ func Parse() {
 
 	if *envDumpYAML {
 		env.Visit(func(v *env.Var) {
-			fmt.Fprintf(outWriter, "- name: %v\n  value: %q\n", v.Name, os.Getenv(v.Name))
+			fmt.Fprintf(outWriter, "- name: %v\n  value: %q\n", v.Name, environ(v))
 		})
 		os.Exit(0)
 	}
@@ -59,7 +62,7 @@ func Parse() {
 				fmt.Fprintf(outWriter, "\n")
 			}
 			first = false
-			fmt.Fprintf(outWriter, "# %v\nexport %v=%q\n", v.Usage, v.Name, os.Getenv(v.Name))
+			fmt.Fprintf(outWriter, "# %v\nexport %v=%q\n", v.Usage, v.Name, environ(v))
 		})
 		os.Exit(0)
 	}
@@ -79,3 +82,12 @@ func Parse() {
 		os.Exit(0)
 	}
 }
+
+// environ returns the value of v in the environment for output, redacted if v is
+// secret.
+func environ(v *env.Var) string {
+	if v.Secret() {
+		return redacted
+	}
+	return os.Getenv(v.Name)
+}
@@ -1,9 +1,12 @@
 package envsvc
 
 import (
+	"bytes"
 	"fmt"
+	"html/template"
 	"io"
 	"net/http"
+	"strings"
 
 	"code.sajari.com/env"
 )
@@ -11,14 +14,33 @@ import (
 // Handler returns the env HTTP Handler.
 //
 // This is only needed to install the handler in a non-standard location.
+//
+// The output format is chosen by the format query parameter: json (default),
+// html or prom (Prometheus text exposition format).  The short query parameter
+// restricts JSON output to variable names and values.  The values of secret
+// variables (see env.Secret) are redacted from all formats.
 func Handler() http.Handler {
 	return http.HandlerFunc(envHandler)
 }
 
 func envHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-
 	vs := r.URL.Query()
+
+	switch format := vs.Get("format"); format {
+	case "", "json":
+	case "html":
+		htmlHandler(w)
+		return
+	case "prom":
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		promHandler(w)
+		return
+	default:
+		http.Error(w, fmt.Sprintf("unknown format %q", format), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	if _, ok := vs["short"]; ok {
 		shortHandler(w)
 		return
@@ -26,6 +48,17 @@ func envHandler(w http.ResponseWriter, r *http.Request) {
 	detailHandler(w)
 }
 
+// redacted replaces the values of secret variables in output.
+const redacted = "xxxxx"
+
+// value returns the value of v for output, redacted if v is secret.
+func value(v *env.Var) string {
+	if v.Secret() {
+		return redacted
+	}
+	return v.Value.String()
+}
+
 func shortHandler(w io.Writer) {
 	fmt.Fprintf(w, "{\n")
 	first := true
@@ -34,7 +67,7 @@ func shortHandler(w io.Writer) {
 			fmt.Fprintf(w, ",\n")
 		}
 		first = false
-		fmt.Fprintf(w, "    %q: %q", v.Name, v.Value.String())
+		fmt.Fprintf(w, "    %q: %q", v.Name, value(v))
 	})
 	fmt.Fprintf(w, "\n}\n")
 }
@@ -47,15 +80,69 @@ func detailHandler(w io.Writer) {
 			fmt.Fprintf(w, ",\n")
 		}
 		first = false
+		constraint, example := env.Describe(v.Value)
 		fmt.Fprintf(w, "        {\n")
 		fmt.Fprintf(w, "            %q: %q,\n", "name", v.Name)
 		fmt.Fprintf(w, "            %q: %q,\n", "usage", v.Usage)
-		fmt.Fprintf(w, "            %q: %q\n", "value", v.Value.String())
+		fmt.Fprintf(w, "            %q: %q,\n", "constraint", constraint)
+		fmt.Fprintf(w, "            %q: %q,\n", "example", example)
+		fmt.Fprintf(w, "            %q: %q,\n", "scope", v.Scope.String())
+		fmt.Fprintf(w, "            %q: %q\n", "value", value(v))
 		fmt.Fprintf(w, "        }")
 	})
 	fmt.Fprintf(w, "\n    ]\n}\n")
 }
 
+type htmlVar struct {
+	Name, Usage, Constraint, Example, Scope, Value string
+}
+
+var htmlTemplate = template.Must(template.New("env").Parse(`<!DOCTYPE html>
+<html>
+<head><title>env</title></head>
+<body>
+<table>
+<tr><th>Name</th><th>Usage</th><th>Constraint</th><th>Example</th><th>Scope</th><th>Value</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{.Usage}}</td><td>{{.Constraint}}</td><td>{{.Example}}</td><td>{{.Scope}}</td><td>{{.Value}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+func htmlHandler(w http.ResponseWriter) {
+	var vars []htmlVar
+	env.Visit(func(v *env.Var) {
+		constraint, example := env.Describe(v.Value)
+		vars = append(vars, htmlVar{
+			Name:       v.Name,
+			Usage:      v.Usage,
+			Constraint: constraint,
+			Example:    example,
+			Scope:      v.Scope.String(),
+			Value:      value(v),
+		})
+	})
+	// Render the page first, so that an error can still be reported with a status.
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, vars); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	buf.WriteTo(w)
+}
+
+var promEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func promHandler(w io.Writer) {
+	fmt.Fprintf(w, "# HELP env_var_info Environment variables defined by the process.\n")
+	fmt.Fprintf(w, "# TYPE env_var_info gauge\n")
+	env.Visit(func(v *env.Var) {
+		constraint, _ := env.Describe(v.Value)
+		fmt.Fprintf(w, "env_var_info{name=\"%s\",constraint=\"%s\"} 1\n", promEscaper.Replace(v.Name), promEscaper.Replace(constraint))
+	})
+}
+
 func init() {
 	http.HandleFunc("/debug/env", envHandler)
 }
@@ -0,0 +1,83 @@
+package envsvc_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"code.sajari.com/env"
+	"code.sajari.com/env/envsvc"
+)
+
+func TestHandlerSecret(t *testing.T) {
+	env.CmdVar = env.NewVarSet("test")
+	env.String("NAME", "service name")
+	env.String("TOKEN", "api token", env.Secret())
+	env.DSN("DB", "database")
+	if err := env.CmdVar.Parse(env.MapGetter{
+		"TEST_NAME":  "svc",
+		"TEST_TOKEN": "s3cret-token",
+		"TEST_DB":    "postgres://user:s3cret-pass@db/app",
+	}); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+
+	for _, query := range []string{"", "?short", "?format=html", "?format=prom"} {
+		w := httptest.NewRecorder()
+		envsvc.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/env"+query, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("%v: got status %v, expected 200", query, w.Code)
+		}
+		if body := w.Body.String(); strings.Contains(body, "s3cret") {
+			t.Errorf("%v: secret value in output:\n%v", query, body)
+		}
+	}
+}
+
+func TestHandlerFormats(t *testing.T) {
+	env.CmdVar = env.NewVarSet("test")
+	env.String("NAME", "service name")
+	env.Int("WORKERS", "number of workers", env.Default("4"))
+	if err := env.CmdVar.Parse(env.MapGetter{"TEST_NAME": "<svc>"}); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+
+	tests := []struct {
+		query       string
+		status      int
+		contentType string
+		want        []string
+	}{
+		{"", http.StatusOK, "application/json; charset=utf-8", []string{
+			`"name": "TEST_NAME"`, `"value": "<svc>"`, `"constraint": "integer"`, `"value": "4"`,
+		}},
+		{"?short", http.StatusOK, "application/json; charset=utf-8", []string{
+			`"TEST_NAME": "<svc>"`, `"TEST_WORKERS": "4"`,
+		}},
+		{"?format=html", http.StatusOK, "text/html; charset=utf-8", []string{
+			"<td>TEST_NAME</td>", "<td>&lt;svc&gt;</td>", "<td>integer</td>",
+		}},
+		{"?format=prom", http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []string{
+			"# TYPE env_var_info gauge\n",
+			`env_var_info{name="TEST_NAME",constraint=""} 1` + "\n",
+			`env_var_info{name="TEST_WORKERS",constraint="integer"} 1` + "\n",
+		}},
+		{"?format=xml", http.StatusBadRequest, "text/plain; charset=utf-8", []string{`unknown format "xml"`}},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		envsvc.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/env"+tt.query, nil))
+		if w.Code != tt.status {
+			t.Errorf("%v: got status %v, expected %v", tt.query, w.Code, tt.status)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != tt.contentType {
+			t.Errorf("%v: got Content-Type %q, expected %q", tt.query, ct, tt.contentType)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(w.Body.String(), want) {
+				t.Errorf("%v: output does not contain %q:\n%v", tt.query, want, w.Body.String())
+			}
+		}
+	}
+}
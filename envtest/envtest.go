@@ -0,0 +1,68 @@
+// Package envtest provides helpers for testing the configuration contract of
+// programs which use env: which variables they require and which values they
+// reject.
+package envtest
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+// RequireParseError parses vs from the environment provided by g and fails the
+// test unless Parse returns errors for exactly the variables named in wantVars
+// (in any order).  Names are full variable names, including the set prefix.  The
+// errors returned by Parse are included in the failure message.
+func RequireParseError(t testing.TB, vs *env.VarSet, g env.Getter, wantVars ...string) {
+	t.Helper()
+
+	err := vs.Parse(g)
+	if err == nil {
+		t.Fatalf("Parse() = nil, expected errors for %v", wantVars)
+		return
+	}
+
+	errs, ok := err.(env.Errors)
+	if !ok {
+		errs = env.Errors{err}
+	}
+
+	var got []string
+	seen := make(map[string]bool)
+	for _, err := range errs {
+		name := failedVar(err)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		got = append(got, name)
+	}
+
+	want := append([]string(nil), wantVars...)
+	sort.Strings(got)
+	sort.Strings(want)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("Parse() failed for %v, expected %v:\n%v", got, want, err)
+	}
+}
+
+// failedVar returns the name of the variable an error from Parse refers to, or
+// the empty string if it is not known.  Checks added by VarSet.Validate name the
+// variable by returning an *env.CheckError.
+func failedVar(err error) string {
+	var missing *env.MissingError
+	var invalid *env.ParseError
+	var check *env.CheckError
+	switch {
+	case errors.As(err, &missing):
+		return missing.Name
+	case errors.As(err, &invalid):
+		return invalid.Name
+	case errors.As(err, &check):
+		return check.Name
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package envtest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"code.sajari.com/env"
+	"code.sajari.com/env/envtest"
+)
+
+type testGetter map[string]string
+
+func (g testGetter) Get(x string) (string, bool) {
+	v, ok := g[x]
+	return v, ok
+}
+
+// recorder is a testing.TB which records failures.
+type recorder struct {
+	testing.TB
+	failed string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.failed = fmt.Sprintf(format, args...)
+}
+
+func TestRequireParseError(t *testing.T) {
+	newVarSet := func() *env.VarSet {
+		vs := env.NewVarSet("svc")
+		min := vs.Int("MIN", "envtest")
+		max := vs.Int("MAX", "envtest")
+		vs.String("NAME", "envtest")
+		vs.Validate(func() error {
+			if *min > *max {
+				return &env.CheckError{Name: vs.Prefix() + "_MIN", Err: fmt.Errorf("greater than %v", vs.Prefix()+"_MAX")}
+			}
+			return nil
+		})
+		return vs
+	}
+
+	tests := []struct {
+		name     string
+		env      testGetter
+		wantVars []string
+		wantFail bool
+	}{
+		{"missing", testGetter{"SVC_MIN": "1", "SVC_MAX": "2"}, []string{"SVC_NAME"}, false},
+		{"invalid", testGetter{"SVC_MIN": "a", "SVC_MAX": "b", "SVC_NAME": "x"}, []string{"SVC_MAX", "SVC_MIN"}, false},
+		{"check", testGetter{"SVC_MIN": "3", "SVC_MAX": "2", "SVC_NAME": "x"}, []string{"SVC_MIN"}, false},
+
+		{"no error", testGetter{"SVC_MIN": "1", "SVC_MAX": "2", "SVC_NAME": "x"}, []string{"SVC_NAME"}, true},
+		{"wrong var", testGetter{"SVC_MIN": "1", "SVC_MAX": "2"}, []string{"SVC_MIN"}, true},
+		{"extra var", testGetter{"SVC_MIN": "1"}, []string{"SVC_NAME"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			envtest.RequireParseError(r, newVarSet(), tt.env, tt.wantVars...)
+			if (r.failed != "") != tt.wantFail {
+				t.Errorf("RequireParseError failed with %q, wantFail %v", r.failed, tt.wantFail)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package env
+
+import "fmt"
+
+// MissingError is returned by Parse for a variable which is unset.
+type MissingError struct {
+	Name string // name of the variable
+}
+
+// Error implements error.
+func (e *MissingError) Error() string {
+	return fmt.Sprintf("missing env %v", e.Name)
+}
+
+// ParseError is returned by Parse for a variable whose value could not be set.
+type ParseError struct {
+	Name  string // name of the variable
+	Value string // value which could not be set
+	Err   error  // error from setting the value
+}
+
+// Error implements error.  The value is not included, as it may be secret.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("could not set env %v: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the error from setting the value.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// CheckError may be returned by a check (see VarSet.Validate) for a constraint on the
+// value of a variable, so that the variable can be identified.
+type CheckError struct {
+	Name string // name of the variable
+	Err  error  // why the check failed
+}
+
+// Error implements error.
+func (e *CheckError) Error() string {
+	return fmt.Sprintf("env %v: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the error from the check.
+func (e *CheckError) Unwrap() error {
+	return e.Err
+}
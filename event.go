@@ -0,0 +1,97 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Event is a structured description of a Parse failure, for sending to error
+// tracking services so that configuration failures can be aggregated and triaged.
+type Event struct {
+	Service    string // name of the VarSet
+	Variable   string // name of the variable, empty for failed checks which return no CheckError
+	Type       string // type of the variable's value, i.e. "int"
+	Constraint string // constraint on the value (see Describe)
+	Reason     string // one of "missing", "invalid" or "check"
+	Message    string // error message
+	Err        error  // underlying error
+}
+
+// Tags returns the fields of the event as a map, for use as tags or extra data.
+func (e Event) Tags() map[string]string {
+	tags := map[string]string{
+		"env.service": e.Service,
+		"env.reason":  e.Reason,
+	}
+	if e.Variable != "" {
+		tags["env.variable"] = e.Variable
+		tags["env.type"] = e.Type
+		tags["env.constraint"] = e.Constraint
+	}
+	return tags
+}
+
+// typeNamer is implemented by the Values of this package to give the name of the
+// type they store, for usage and schemas.
+type typeNamer interface {
+	typeName() string
+}
+
+// valueType returns a short name for the type of the Value: the name given by the
+// Value if it is defined by this package, or else the name of its Go type.
+func valueType(v Value) string {
+	if cv, ok := v.(checkedValue); ok {
+		v = cv.Value
+	}
+	if tn, ok := v.(typeNamer); ok {
+		return tn.typeName()
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", v), "*")
+}
+
+// Events converts an error returned by Parse into a list of events, one for each
+// failure.
+func (v *VarSet) Events(err error) []Event {
+	if err == nil {
+		return nil
+	}
+	errs := []error{err}
+	if es, ok := err.(Errors); ok {
+		errs = es
+	}
+
+	vars := make(map[string]*Var)
+	v.Visit(func(x *Var) { vars[x.Name] = x })
+
+	events := make([]Event, 0, len(errs))
+	for _, err := range errs {
+		e := Event{Service: v.name, Reason: "check", Message: err.Error(), Err: err}
+
+		var missing *MissingError
+		var invalid *ParseError
+		var check *CheckError
+		switch {
+		case errors.As(err, &missing):
+			e.Reason, e.Variable = "missing", missing.Name
+		case errors.As(err, &invalid):
+			e.Reason, e.Variable = "invalid", invalid.Name
+		case errors.As(err, &check):
+			e.Variable = check.Name
+		}
+		if x, ok := vars[e.Variable]; ok {
+			e.Type = valueType(x.Value)
+			e.Constraint, _ = Describe(x.Value)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// OnEvent makes Parse call fn with an Event for each failure (see VarSet.Events), for
+// routing configuration failures to error tracking.
+func OnEvent(fn func(Event)) ParseOption {
+	return func(c *parseConfig) {
+		c.onEvent = fn
+	}
+}
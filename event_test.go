@@ -0,0 +1,45 @@
+package env_test
+
+import (
+	"errors"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestEvents(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	vs.Int("WORKERS", "event test")
+	vs.BindAddr("LISTEN", "event test")
+	vs.Validate(func() error { return errors.New("check failed") })
+
+	var events []env.Event
+	err := vs.Parse(testGetter{"SVC_WORKERS": "x", "SVC_LISTEN": ":80"}, env.OnEvent(func(e env.Event) { events = append(events, e) }))
+	if err == nil {
+		t.Fatalf("expected error from Parse")
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, expected 1", len(events))
+	}
+	e := events[0]
+	if e.Service != "svc" || e.Variable != "SVC_WORKERS" || e.Type != "int" || e.Constraint != "integer" || e.Reason != "invalid" {
+		t.Errorf("got %+v, unexpected fields", e)
+	}
+	if tags := e.Tags(); tags["env.variable"] != "SVC_WORKERS" || tags["env.reason"] != "invalid" {
+		t.Errorf("got tags %v, unexpected values", tags)
+	}
+
+	events = vs.Events(vs.Parse(testGetter{"SVC_WORKERS": "1"}))
+	if len(events) != 1 || events[0].Variable != "SVC_LISTEN" || events[0].Type != "string" || events[0].Reason != "missing" {
+		t.Errorf("got %+v, expected missing SVC_LISTEN", events)
+	}
+
+	events = vs.Events(vs.Parse(testGetter{"SVC_WORKERS": "1", "SVC_LISTEN": ":80"}))
+	if len(events) != 1 || events[0].Variable != "" || events[0].Reason != "check" || events[0].Message != "check failed" {
+		t.Errorf("got %+v, expected failed check", events)
+	}
+
+	if events := vs.Events(nil); events != nil {
+		t.Errorf("got %+v, expected no events", events)
+	}
+}
@@ -0,0 +1,93 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// expandGetter is a Getter which expands ${NAME} references in the values of another
+// Getter.
+type expandGetter struct {
+	Getter
+}
+
+func (g expandGetter) Get(x string) (string, bool) {
+	z, ok, _ := g.GetContext(context.Background(), x)
+	return z, ok
+}
+
+func (g expandGetter) GetContext(ctx context.Context, x string) (string, bool, error) {
+	return g.expand(ctx, x, nil)
+}
+
+// expand returns the value of x with references expanded, where stack is the list of
+// variables whose values reference x.
+func (g expandGetter) expand(ctx context.Context, x string, stack []string) (string, bool, error) {
+	for i, name := range stack {
+		if name == x {
+			return "", false, fmt.Errorf("env %v: reference cycle %v", stack[0], strings.Join(append(stack[i:], x), " -> "))
+		}
+	}
+
+	z, ok, err := getContext(ctx, g.Getter, x)
+	if !ok || err != nil || !strings.Contains(z, "$") {
+		return z, ok, err
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(z, "$")
+		if i < 0 || i == len(z)-1 {
+			b.WriteString(z)
+			break
+		}
+		b.WriteString(z[:i])
+		switch z[i+1] {
+		case '$':
+			b.WriteByte('$')
+			z = z[i+2:]
+			continue
+		case '{':
+		default:
+			b.WriteByte('$')
+			z = z[i+1:]
+			continue
+		}
+
+		j := strings.Index(z[i:], "}")
+		if j < 0 {
+			return "", false, fmt.Errorf("env %v: unterminated reference in %q", x, z[i:])
+		}
+		ref := z[i+2 : i+j]
+		if ref == "" {
+			return "", false, fmt.Errorf("env %v: empty reference", x)
+		}
+		rz, ok, err := g.expand(ctx, ref, append(stack, x))
+		if err != nil {
+			return "", false, err
+		}
+		if !ok {
+			return "", false, fmt.Errorf("env %v: reference ${%v} is unset", x, ref)
+		}
+		b.WriteString(rz)
+		z = z[i+j+1:]
+	}
+	return b.String(), true, nil
+}
+
+// Expand returns a Getter which expands references to other variables of the form
+// ${NAME} in the values of g, so that values can be composed from other variables:
+//
+//	DB_HOST=db.internal
+//	DB_URL=postgres://${DB_HOST}:5432/app   ; postgres://db.internal:5432/app
+//
+// References are expanded recursively.  $$ is replaced by a single $, and a $ which is
+// not followed by { is left as is.  References which are unset, unterminated or form a
+// cycle are errors.
+//
+// The returned Getter is also a ContextGetter, which reports these errors.  Get reports
+// them as unset variables.
+func Expand(g Getter) Getter {
+	return expandGetter{g}
+}
@@ -0,0 +1,57 @@
+package env_test
+
+import (
+	"context"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestExpand(t *testing.T) {
+	g := env.Expand(testGetter{
+		"DB_HOST":   "db.internal",
+		"DB_PORT":   "5432",
+		"DB_ADDR":   "${DB_HOST}:${DB_PORT}",
+		"DB_URL":    "postgres://${DB_ADDR}/app",
+		"PRICE":     "$$5 or $6$",
+		"CYCLE_A":   "x${CYCLE_B}",
+		"CYCLE_B":   "y${CYCLE_A}",
+		"SELF":      "${SELF}",
+		"DANGLING":  "${UNSET}",
+		"OPEN":      "${DB_HOST",
+		"EMPTY_REF": "a${}b",
+		"PLAIN":     "no references",
+	})
+
+	tests := []struct {
+		name    string
+		out     string
+		ok      bool
+		wantErr bool
+	}{
+		{"DB_URL", "postgres://db.internal:5432/app", true, false},
+		{"PRICE", "$5 or $6$", true, false},
+		{"PLAIN", "no references", true, false},
+		{"UNSET", "", false, false},
+		{"CYCLE_A", "", false, true},
+		{"SELF", "", false, true},
+		{"DANGLING", "", false, true},
+		{"OPEN", "", false, true},
+		{"EMPTY_REF", "", false, true},
+	}
+
+	cg := g.(env.ContextGetter)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			z, ok, err := cg.GetContext(context.Background(), tt.name)
+			if z != tt.out || ok != tt.ok || (err != nil) != tt.wantErr {
+				t.Errorf("GetContext(%q) = (%q, %v, %v), expected (%q, %v, error %v)", tt.name, z, ok, err, tt.out, tt.ok, tt.wantErr)
+			}
+		})
+	}
+
+	_, _, err := cg.GetContext(context.Background(), "CYCLE_A")
+	if expected := "env CYCLE_A: reference cycle CYCLE_A -> CYCLE_B -> CYCLE_A"; err == nil || err.Error() != expected {
+		t.Errorf("got error %v, expected %q", err, expected)
+	}
+}
@@ -0,0 +1,78 @@
+package env
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Save writes the values of the variables in the set, as last successfully parsed
+// from the environment, to the file at path so that they can be used by LoadFallback
+// if a later Parse fails.  Variables which took their default values are not saved.
+//
+// The file may contain secrets: it is created with mode 0600 and replaced atomically.
+func (v *VarSet) Save(path string) error {
+	values := make(map[string]string)
+	v.Visit(func(x *Var) {
+		if x.source != "" {
+			values[x.source] = x.raw
+		}
+	})
+
+	b, err := json.MarshalIndent(values, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, append(b, '\n'))
+}
+
+// writeFileAtomic writes b to a temporary file alongside path with mode 0600, then
+// renames it to path, so that readers never see a partially written file.
+func writeFileAtomic(path string, b []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// LoadFallback returns a Getter for the values written to the file at path by Save.
+// It is used to fall back to the last known good configuration when Parse fails:
+//
+//	if err := vs.Parse(env); err != nil {
+//		g, ferr := env.LoadFallback(path)
+//		if ferr != nil || vs.Parse(g) != nil {
+//			return err
+//		}
+//		log.Printf("using last known good configuration: %v", err)
+//	}
+//
+// Whether to fall back, and for which errors, is left to the caller.
+func LoadFallback(path string) (Getter, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]string
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, err
+	}
+	return MapGetter(values), nil
+}
+
+// Save writes the values of the variables in the default set, as last parsed from
+// the environment, to the file at path (see VarSet.Save).
+func Save(path string) error {
+	return CmdVar.Save(path)
+}
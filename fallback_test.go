@@ -0,0 +1,55 @@
+package env_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestSaveLoadFallback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "good.json")
+
+	vs := env.NewVarSet("svc")
+	workers := vs.Int("WORKERS", "fallback test", env.Alias("THREADS"))
+	name := vs.String("NAME", "fallback test", env.Default("a"))
+
+	if err := vs.Parse(testGetter{"SVC_THREADS": "4"}); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+	if err := vs.Save(path); err != nil {
+		t.Fatalf("unexpected error from Save: %v", err)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0600 {
+		t.Errorf("got (%v, %v), expected mode 0600", fi, err)
+	}
+
+	if err := vs.Parse(testGetter{"SVC_THREADS": "x", "SVC_NAME": "b"}); err == nil {
+		t.Fatalf("expected error from Parse")
+	}
+
+	g, err := env.LoadFallback(path)
+	if err != nil {
+		t.Fatalf("unexpected error from LoadFallback: %v", err)
+	}
+	if z, ok := g.Get("SVC_NAME"); ok {
+		t.Errorf("g.Get(\"SVC_NAME\") = (%q, %v), expected default not to be saved", z, ok)
+	}
+	if err := vs.Parse(g); err != nil {
+		t.Errorf("unexpected error from Parse with fallback: %v", err)
+	}
+	if *workers != 4 || *name != "a" {
+		t.Errorf("got (%d, %q), expected (4, \"a\")", *workers, *name)
+	}
+
+	if _, err := env.LoadFallback(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("expected error loading missing file")
+	}
+}
@@ -0,0 +1,166 @@
+package env
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileCache is a Getter which keeps an encrypted on-disk copy of the values retrieved
+// from a remote Getter, so that a service can restart using the last known good values
+// while the remote store is unavailable.
+//
+// Values are looked up in the remote Getter first, and are written to the cache when
+// they change.  If the remote Getter fails (see ContextGetter) then the cached value is
+// used instead, provided it is no older than TTL, and the name is reported by Stale.
+// A cache file which cannot be read (i.e. after the key is rotated) is replaced while
+// the remote Getter is available.
+//
+// Loading the key (i.e. from the OS keychain or a KMS) is left to the caller, as is
+// reporting the use of cached values: check Stale after parsing, i.e.
+//
+//	err := vs.Parse(cache)
+//	if stale := cache.Stale(); len(stale) > 0 {
+//		log.Printf("using cached values for %v", strings.Join(stale, ", "))
+//	}
+type FileCache struct {
+	Getter Getter        // remote Getter
+	Path   string        // cache file
+	Key    []byte        // AES key: 16, 24 or 32 bytes
+	TTL    time.Duration // maximum age of cached values, zero for no limit
+	Clock  Clock         // clock for cache times, the system clock if nil
+
+	mu      sync.Mutex
+	entries map[string]fileCacheEntry
+	stale   map[string]bool
+}
+
+type fileCacheEntry struct {
+	Value string    `json:"value"`
+	Time  time.Time `json:"time"`
+}
+
+// Get implements Getter.
+func (c *FileCache) Get(name string) (string, bool) {
+	z, ok, _ := c.GetContext(context.Background(), name)
+	return z, ok
+}
+
+// GetContext implements ContextGetter.  It only returns an error if the remote Getter
+// fails and there is no usable cached value.
+func (c *FileCache) GetContext(ctx context.Context, name string) (string, bool, error) {
+	var z string
+	var ok bool
+	var err error
+	if cg, isContext := c.Getter.(ContextGetter); isContext {
+		z, ok, err = cg.GetContext(ctx, name)
+	} else {
+		z, ok = c.Getter.Get(name)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		entries, loadErr := c.load()
+		if loadErr != nil {
+			// The cache is only needed if the remote Getter fails.
+			if err != nil {
+				return "", false, fmt.Errorf("%v (%v)", err, loadErr)
+			}
+			entries = make(map[string]fileCacheEntry)
+		}
+		c.entries = entries
+	}
+
+	if err != nil {
+		e, cached := c.entries[name]
+		if !cached || c.TTL > 0 && c.now().Sub(e.Time) > c.TTL {
+			return "", false, err
+		}
+		if c.stale == nil {
+			c.stale = make(map[string]bool)
+		}
+		c.stale[name] = true
+		return e.Value, true, nil
+	}
+
+	if ok {
+		if e, cached := c.entries[name]; !cached || e.Value != z {
+			c.entries[name] = fileCacheEntry{Value: z, Time: c.now()}
+			if err := c.save(); err != nil {
+				return "", false, err
+			}
+		}
+	}
+	return z, ok, nil
+}
+
+// Stale returns the sorted names of the variables which were served from the cache
+// because the remote Getter failed.
+func (c *FileCache) Stale() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stale := make([]string, 0, len(c.stale))
+	for name := range c.stale {
+		stale = append(stale, name)
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+func (c *FileCache) now() time.Time {
+	if c.Clock == nil {
+		return time.Now()
+	}
+	return c.Clock.Now()
+}
+
+// load reads and decrypts the cache file.  A missing file is an empty cache.
+func (c *FileCache) load() (map[string]fileCacheEntry, error) {
+	entries := make(map[string]fileCacheEntry)
+	b, err := ioutil.ReadFile(c.Path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := aesGCM(c.Key)
+	if err != nil {
+		return nil, err
+	}
+	b, err = open(aead, b)
+	if err != nil {
+		return nil, errors.New("env cache " + c.Path + ": " + err.Error())
+	}
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// save encrypts and writes the cache file, replacing it atomically.
+func (c *FileCache) save() error {
+	b, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+
+	aead, err := aesGCM(c.Key)
+	if err != nil {
+		return err
+	}
+	b, err = seal(aead, b)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.Path, b)
+}
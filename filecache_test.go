@@ -0,0 +1,80 @@
+package env_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"code.sajari.com/env"
+)
+
+func TestFileCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cache")
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := env.ClockFunc(func() time.Time { return now })
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	remote := remoteGetter{testGetter: testGetter{"TOKEN": "t1"}}
+	c := &env.FileCache{Getter: remote, Path: path, Key: key, TTL: time.Hour, Clock: clock}
+	if z, ok, err := c.GetContext(context.Background(), "TOKEN"); err != nil || !ok || z != "t1" {
+		t.Errorf("c.GetContext() = (%q, %v, %v), expected (\"t1\", true, nil)", z, ok, err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read cache: %v", err)
+	}
+	if len(b) == 0 || bytes.Contains(b, []byte("t1")) {
+		t.Errorf("cache file is empty or not encrypted: %q", b)
+	}
+
+	remote.errs = map[string]error{"TOKEN": errors.New("unavailable"), "OTHER": errors.New("unavailable")}
+	now = now.Add(30 * time.Minute)
+	c = &env.FileCache{Getter: remote, Path: path, Key: key, TTL: time.Hour, Clock: clock}
+	if z, ok, err := c.GetContext(context.Background(), "TOKEN"); err != nil || !ok || z != "t1" {
+		t.Errorf("c.GetContext() = (%q, %v, %v), expected cached (\"t1\", true, nil)", z, ok, err)
+	}
+	if _, _, err := c.GetContext(context.Background(), "OTHER"); err == nil {
+		t.Errorf("expected error for uncached variable")
+	}
+	c.GetContext(context.Background(), "TOKEN")
+	if got := c.Stale(); !reflect.DeepEqual(got, []string{"TOKEN"}) {
+		t.Errorf("c.Stale() = %q, expected [\"TOKEN\"]", got)
+	}
+
+	now = now.Add(time.Hour)
+	c = &env.FileCache{Getter: remote, Path: path, Key: key, TTL: time.Hour, Clock: clock}
+	if _, _, err := c.GetContext(context.Background(), "TOKEN"); err == nil {
+		t.Errorf("expected error for expired cached variable")
+	}
+
+	c = &env.FileCache{Getter: remote, Path: path, Key: []byte("fedcba9876543210fedcba9876543210")}
+	if _, _, err := c.GetContext(context.Background(), "TOKEN"); err == nil {
+		t.Errorf("expected error with wrong key")
+	}
+
+	// A cache which cannot be read is replaced while the remote Getter is available.
+	remote.errs = nil
+	newKey := []byte("fedcba9876543210fedcba9876543210")
+	c = &env.FileCache{Getter: remote, Path: path, Key: newKey}
+	if z, ok, err := c.GetContext(context.Background(), "TOKEN"); err != nil || !ok || z != "t1" {
+		t.Errorf("c.GetContext() = (%q, %v, %v), expected (\"t1\", true, nil) with unreadable cache", z, ok, err)
+	}
+	remote.errs = map[string]error{"TOKEN": errors.New("unavailable")}
+	c = &env.FileCache{Getter: remote, Path: path, Key: newKey}
+	if z, ok, err := c.GetContext(context.Background(), "TOKEN"); err != nil || !ok || z != "t1" {
+		t.Errorf("c.GetContext() = (%q, %v, %v), expected cached (\"t1\", true, nil) with new key", z, ok, err)
+	}
+}
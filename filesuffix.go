@@ -0,0 +1,54 @@
+package env
+
+import (
+	"context"
+	"fmt"
+)
+
+// fileSuffixGetter is a Getter which reads NAME from the file named by NAME_FILE if
+// NAME is unset.
+type fileSuffixGetter struct {
+	Getter
+}
+
+func (g fileSuffixGetter) Get(x string) (string, bool) {
+	z, ok, _ := g.GetContext(context.Background(), x)
+	return z, ok
+}
+
+func (g fileSuffixGetter) GetContext(ctx context.Context, x string) (string, bool, error) {
+	if z, ok, err := getContext(ctx, g.Getter, x); ok || err != nil {
+		return z, ok, err
+	}
+	path, ok, err := getContext(ctx, g.Getter, x+"_FILE")
+	if !ok || err != nil {
+		return "", false, err
+	}
+	z, err := readFileValue(path)
+	if err != nil {
+		return "", false, fmt.Errorf("env %v_FILE: %v", x, err)
+	}
+	return z, true, nil
+}
+
+// getContext looks up x in g, using GetContext if g is a ContextGetter.
+func getContext(ctx context.Context, g Getter, x string) (string, bool, error) {
+	if cg, ok := g.(ContextGetter); ok {
+		return cg.GetContext(ctx, x)
+	}
+	z, ok := g.Get(x)
+	return z, ok, nil
+}
+
+// FileSuffix returns a Getter which follows the NAME_FILE convention used by official
+// Docker images: if NAME is unset in g but NAME_FILE is set, the value of NAME is read
+// from the file at the path given by NAME_FILE, with a single trailing newline removed.
+// If both are set then NAME takes precedence:
+//
+//	g := env.FileSuffix(env.OS())   // DB_PASSWORD_FILE=/run/secrets/db_password
+//
+// The returned Getter is also a ContextGetter, which reports errors reading the file.
+// Get reports these as unset variables.
+func FileSuffix(g Getter) Getter {
+	return fileSuffixGetter{g}
+}
@@ -0,0 +1,60 @@
+package env_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestFileSuffix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "db_password")
+	if err := ioutil.WriteFile(path, []byte("s3cret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	g := env.FileSuffix(testGetter{
+		"DB_PASSWORD_FILE": path,
+		"API_KEY":          "direct",
+		"API_KEY_FILE":     path,
+		"MISSING_FILE":     filepath.Join(dir, "missing"),
+	})
+
+	tests := []struct {
+		name    string
+		out     string
+		ok      bool
+		wantErr bool
+	}{
+		{"DB_PASSWORD", "s3cret", true, false},
+		{"API_KEY", "direct", true, false},
+		{"UNSET", "", false, false},
+		{"MISSING", "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			z, ok, err := g.(env.ContextGetter).GetContext(context.Background(), tt.name)
+			if z != tt.out || ok != tt.ok || (err != nil) != tt.wantErr {
+				t.Errorf("GetContext(%q) = (%q, %v, %v), expected (%q, %v, error %v)", tt.name, z, ok, err, tt.out, tt.ok, tt.wantErr)
+			}
+		})
+	}
+
+	vs := env.NewVarSet("")
+	password := vs.String("DB_PASSWORD", "database password", env.Secret())
+	if err := vs.Parse(g); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+	if *password != "s3cret" {
+		t.Errorf("got %q, expected %q", *password, "s3cret")
+	}
+}
@@ -0,0 +1,103 @@
+package env
+
+import (
+	"flag"
+	"strings"
+)
+
+// FromFlagValue returns f as a Value, so that existing flag.Value implementations
+// can be defined in a VarSet using Var.
+//
+// Value has the same method set as flag.Value, so f is returned unchanged: any
+// flag.Value can also be passed to Var directly.
+func FromFlagValue(f flag.Value) Value {
+	return f
+}
+
+// ToFlagValue returns v as a flag.Value, so that Value implementations can be
+// registered with a flag.FlagSet using Var.
+//
+// Value has the same method set as flag.Value, so v is returned unchanged.
+func ToFlagValue(v Value) flag.Value {
+	return v
+}
+
+// flagName returns the name of the flag for x registered by BindFlags for a set with
+// given prefix: the variable name without the prefix, lower-cased, with underscores
+// replaced by dashes.  Variables of sub-sets keep the names of the sub-sets.
+func flagName(prefix string, x *Var) string {
+	name := x.Name
+	if prefix != "" {
+		name = strings.TrimPrefix(name, prefix+"_")
+	}
+	return strings.Replace(strings.ToLower(name), "_", "-", -1)
+}
+
+// flagRecorder is a flag.Value which records the value given on the command line, to
+// be parsed later by the variable.
+type flagRecorder struct {
+	x      *Var
+	values map[string]string
+}
+
+func (f flagRecorder) String() string {
+	if f.x == nil {
+		return ""
+	}
+	if z, ok := f.values[f.x.Name]; ok {
+		return z
+	}
+	if f.x.def != nil && !f.x.secret {
+		return f.x.def()
+	}
+	return ""
+}
+
+func (f flagRecorder) Set(z string) error {
+	f.values[f.x.Name] = z
+	return nil
+}
+
+func (f flagRecorder) IsBoolFlag() bool {
+	v := f.x.Value
+	if cv, ok := v.(checkedValue); ok {
+		v = cv.Value
+	}
+	if _, ok := v.(*boolValue); ok {
+		return true
+	}
+	b, ok := v.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// BindFlags registers a flag with fs for every variable in the set, named after the
+// variable without the set prefix, lower-cased and with underscores replaced by dashes
+// (i.e. MYAPP_DB_HOST is -db-host).  It returns a Getter for the values of the flags
+// given on the command line, which should be chained before other Getters so that
+// flags take precedence over the environment, and the environment over defaults:
+//
+//	flags := vs.BindFlags(flag.CommandLine)
+//	flag.Parse()
+//	err := vs.Parse(env.Chain(flags, env.OS()))
+//
+// Flag values are parsed and validated by Parse, in the same way as values from the
+// environment.  BindFlags panics if a flag is already defined in fs (see
+// flag.FlagSet.Var), so it should only be called once for each FlagSet.
+func (v *VarSet) BindFlags(fs *flag.FlagSet) Getter {
+	values := make(MapGetter)
+	v.Visit(func(x *Var) {
+		fs.Var(flagRecorder{x: x, values: values}, flagName(v.prefix, x), x.Usage+" (env "+x.Name+")")
+	})
+	return values
+}
+
+// ParseFlags registers flags for the variables in the set with fs (see BindFlags),
+// parses args with fs, and then parses the set from the flags given on the command
+// line and g, in that order of precedence.
+func (v *VarSet) ParseFlags(fs *flag.FlagSet, args []string, g Getter, opts ...ParseOption) error {
+	flags := v.BindFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return v.Parse(Chain(flags, g), opts...)
+}
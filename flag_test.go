@@ -0,0 +1,77 @@
+package env_test
+
+import (
+	"flag"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestFlagValue(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	n := fs.Int("n", 0, "int flag")
+	f := fs.Lookup("n").Value
+
+	vs := env.NewVarSet("")
+	vs.Var(env.FromFlagValue(f), "N", "flag value test")
+	if err := vs.Parse(testGetter{"N": "42"}); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+	if *n != 42 {
+		t.Errorf("*n = %d, expected 42", *n)
+	}
+
+	var p positiveInteger
+	fs.Var(env.ToFlagValue(&p), "p", "env value test")
+	if err := fs.Parse([]string{"-p", "7"}); err != nil {
+		t.Fatalf("unexpected error from fs.Parse: %v", err)
+	}
+	if p != 7 {
+		t.Errorf("p = %d, expected 7", p)
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	vs := env.NewVarSet("myapp")
+	host := vs.String("DB_HOST", "database host", env.Default("localhost"))
+	port := vs.Int("DB_PORT", "database port", env.Default("5432"))
+	debug := vs.Bool("DEBUG", "debug logging", env.Optional())
+	name := vs.String("NAME", "service name")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	args := []string{"-db-host", "db.internal", "-debug"}
+	if err := vs.ParseFlags(fs, args, testGetter{"MYAPP_DB_HOST": "env.internal", "MYAPP_DB_PORT": "6543", "MYAPP_NAME": "svc"}); err != nil {
+		t.Fatalf("unexpected error from ParseFlags: %v", err)
+	}
+	if *host != "db.internal" || *port != 6543 || !*debug || *name != "svc" {
+		t.Errorf("got (%q, %d, %v, %q), expected (\"db.internal\", 6543, true, \"svc\")", *host, *port, *debug, *name)
+	}
+	if f := fs.Lookup("db-port"); f == nil || f.DefValue != "5432" || f.Usage != "database port (env MYAPP_DB_PORT)" {
+		t.Errorf("got flag %+v, expected -db-port with default 5432", f)
+	}
+
+	vs = env.NewVarSet("myapp")
+	vs.Int("DB_PORT", "database port")
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	if err := vs.ParseFlags(fs, []string{"-db-port", "x"}, testGetter{"MYAPP_DB_PORT": "5432"}); err == nil {
+		t.Errorf("expected error for invalid flag value")
+	}
+}
+
+func TestParseFlagsSub(t *testing.T) {
+	vs := env.NewVarSet("myapp")
+	host := vs.String("HOST", "service host")
+	dbHost := vs.Sub("db").String("HOST", "database host")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	args := []string{"-host", "svc.internal", "-db-host", "db.internal"}
+	if err := vs.ParseFlags(fs, args, testGetter{}); err != nil {
+		t.Fatalf("unexpected error from ParseFlags: %v", err)
+	}
+	if *host != "svc.internal" || *dbHost != "db.internal" {
+		t.Errorf("got (%q, %q), expected (\"svc.internal\", \"db.internal\")", *host, *dbHost)
+	}
+	if f := fs.Lookup("db-host"); f == nil || f.Usage != "database host (env MYAPP_DB_HOST)" {
+		t.Errorf("got flag %+v, expected -db-host for MYAPP_DB_HOST", f)
+	}
+}
@@ -0,0 +1,36 @@
+package env
+
+import "strings"
+
+// defaultOutputFormats are the formats accepted by OutputFormat when none are given.
+var defaultOutputFormats = []string{"text", "json", "table"}
+
+// OutputFormat defines a string variable with specified name and usage string, which must be
+// one of formats (or text, json or table if formats is empty).  The first format is
+// used as the example value in generated documentation.
+//
+// The variable is looked up by its prefixed name (i.e. MYAPP_FORMAT), and then by name
+// without the set prefix (i.e. FORMAT), so that CLI tools can share the conventional
+// unprefixed variable.
+// The return value is the address of a string variable that stores the value of the variable.
+func (v *VarSet) OutputFormat(name, usage string, formats []string, opts ...Option) *string {
+	if len(formats) == 0 {
+		formats = defaultOutputFormats
+	}
+	p := new(string)
+	v.Var(checkedValue{
+		fn:         isOneOf(formats...),
+		schema:     enumSchema(formats...),
+		constraint: "one of " + strings.Join(formats, ", "),
+		example:    formats[0],
+		Value:      newStringValue("", p),
+	}, name, usage, append([]Option{FallbackTo(name)}, opts...)...)
+	return p
+}
+
+// OutputFormat defines a string variable with specified name and usage string, which must be
+// one of formats (see VarSet.OutputFormat).
+// The return value is the address of a string variable that stores the value of the variable.
+func OutputFormat(name, usage string, formats []string, opts ...Option) *string {
+	return CmdVar.OutputFormat(name, usage, formats, opts...)
+}
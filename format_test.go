@@ -0,0 +1,50 @@
+package env_test
+
+import (
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestOutputFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		formats []string
+		env     testGetter
+		out     string
+		wantErr bool
+	}{
+		{"prefixed", nil, testGetter{"APP_FORMAT": "json", "FORMAT": "table"}, "json", false},
+		{"unprefixed", nil, testGetter{"FORMAT": "table"}, "table", false},
+		{"custom", []string{"yaml", "csv"}, testGetter{"APP_FORMAT": "csv"}, "csv", false},
+
+		{"missing", nil, testGetter{}, "", true},
+		{"invalid", nil, testGetter{"APP_FORMAT": "xml"}, "", true},
+		{"invalid custom", []string{"yaml", "csv"}, testGetter{"APP_FORMAT": "json"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vs := env.NewVarSet("app")
+			format := vs.OutputFormat("FORMAT", "format test", tt.formats)
+
+			if err := vs.Parse(tt.env); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if *format != tt.out {
+				t.Errorf(" = %q, expected %q", *format, tt.out)
+			}
+		})
+	}
+}
+
+func TestOutputFormatOptions(t *testing.T) {
+	vs := env.NewVarSet("app")
+	format := vs.OutputFormat("FORMAT", "format test", nil, env.Default("json"))
+	if err := vs.Parse(testGetter{}); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *format != "json" {
+		t.Errorf(" = %q, expected \"json\"", *format)
+	}
+}
@@ -0,0 +1,335 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// groupName returns the name of a variable in a group with the given name prefix.
+// If name is empty then suffix is used unprefixed.
+func groupName(name, suffix string) string {
+	if name == "" {
+		return suffix
+	}
+	return name + "_" + suffix
+}
+
+// LDAPConfig is the configuration for connecting to an LDAP directory.
+type LDAPConfig struct {
+	URL          string // server URL, ldap:// or ldaps://
+	BindDN       string // DN to bind as, empty for anonymous bind
+	BindPassword string // password for BindDN
+	BaseDN       string // base DN for searches
+	TLSMode      string // one of "none", "starttls" or "tls"
+}
+
+// LDAP defines a group of variables with specified name prefix and usage string which
+// configure a connection to an LDAP directory:
+//
+//	NAME_URL            server URL (ldap://host:389 or ldaps://host:636)
+//	NAME_BIND_DN        DN to bind as (optional, unset or empty for anonymous bind)
+//	NAME_BIND_PASSWORD  password for NAME_BIND_DN (optional, Secret)
+//	NAME_BASE_DN        base DN for searches
+//	NAME_TLS_MODE       none, starttls or tls
+//
+// Parse checks that the TLS mode is consistent with the URL scheme (ldaps:// requires
+// tls), and that a bind DN and password are either both given or both empty.
+// The return value is the address of an LDAPConfig that stores the values of the variables.
+func (v *VarSet) LDAP(name, usage string, opts ...Option) *LDAPConfig {
+	c := new(LDAPConfig)
+	urlVar := v.define(checkedValue{
+		fn:         isLDAPURL,
+		constraint: "LDAP URL (ldap:// or ldaps://)",
+		example:    "ldaps://ldap.example.com:636",
+		Value:      newStringValue("", &c.URL),
+	}, groupName(name, "URL"), usage+" (server URL)", opts...)
+	bindDNVar := v.define(newStringValue("", &c.BindDN), groupName(name, "BIND_DN"), usage+" (bind DN, empty for anonymous bind)", append([]Option{Optional()}, opts...)...)
+	bindPasswordVar := v.define(newStringValue("", &c.BindPassword), groupName(name, "BIND_PASSWORD"), usage+" (bind password)", append([]Option{Optional(), Secret()}, opts...)...)
+	v.Var(checkedValue{
+		fn:         isDN,
+		constraint: "distinguished name",
+		example:    "dc=example,dc=com",
+		Value:      newStringValue("", &c.BaseDN),
+	}, groupName(name, "BASE_DN"), usage+" (base DN)", opts...)
+	v.Var(checkedValue{
+		fn:         isOneOf("none", "starttls", "tls"),
+		schema:     enumSchema("none", "starttls", "tls"),
+		constraint: "one of none, starttls, tls",
+		example:    "starttls",
+		Value:      newStringValue("", &c.TLSMode),
+	}, groupName(name, "TLS_MODE"), usage+" (TLS mode)", opts...)
+
+	v.Validate(func() error {
+		u, _ := url.Parse(c.URL)
+		switch {
+		case u.Scheme == "ldaps" && c.TLSMode != "tls":
+			return &CheckError{Name: urlVar.Name, Err: fmt.Errorf("ldaps:// requires TLS mode tls, got %v", c.TLSMode)}
+		case u.Scheme == "ldap" && c.TLSMode == "tls":
+			return &CheckError{Name: urlVar.Name, Err: errors.New("ldap:// cannot be used with TLS mode tls, use ldaps:// or starttls")}
+		case c.BindDN != "" && c.BindPassword == "":
+			return &CheckError{Name: bindPasswordVar.Name, Err: errors.New("required when bind DN is set")}
+		case c.BindDN == "" && c.BindPassword != "":
+			return &CheckError{Name: bindDNVar.Name, Err: errors.New("required when bind password is set")}
+		}
+		return nil
+	})
+	return c
+}
+
+// isLDAPURL checks if x is an ldap:// or ldaps:// URL with a host.
+func isLDAPURL(x string) error {
+	u, err := url.Parse(x)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return fmt.Errorf("invalid scheme %q, expected ldap or ldaps", u.Scheme)
+	}
+	if u.Host == "" {
+		return errors.New("empty host")
+	}
+	return nil
+}
+
+// isDN checks if x is a non-empty list of comma separated attribute=value pairs.
+func isDN(x string) error {
+	if x == "" {
+		return errors.New("empty DN")
+	}
+	for _, rdn := range strings.Split(x, ",") {
+		i := strings.Index(rdn, "=")
+		if i <= 0 || i == len(rdn)-1 {
+			return fmt.Errorf("invalid RDN %q", rdn)
+		}
+	}
+	return nil
+}
+
+// KerberosConfig is the configuration for authenticating with Kerberos.
+type KerberosConfig struct {
+	Realm     string // realm, i.e. EXAMPLE.COM
+	Keytab    string // path to keytab file
+	Principal string // service principal, i.e. HTTP/host.example.com@EXAMPLE.COM
+}
+
+// Kerberos defines a group of variables with specified name prefix and usage string which
+// configure Kerberos authentication:
+//
+//	NAME_REALM      realm (i.e. EXAMPLE.COM)
+//	NAME_KEYTAB     path to an existing keytab file
+//	NAME_PRINCIPAL  principal (i.e. HTTP/host.example.com or HTTP/host.example.com@EXAMPLE.COM)
+//
+// Parse checks that the realm of the principal, if given, matches NAME_REALM.
+// The return value is the address of a KerberosConfig that stores the values of the variables.
+func (v *VarSet) Kerberos(name, usage string, opts ...Option) *KerberosConfig {
+	c := new(KerberosConfig)
+	realmVar := v.define(checkedValue{
+		fn:         isNonEmpty,
+		constraint: "non-empty string",
+		example:    "EXAMPLE.COM",
+		Value:      newStringValue("", &c.Realm),
+	}, groupName(name, "REALM"), usage+" (realm)", opts...)
+	v.Var(checkedValue{
+		fn:         isFile,
+		constraint: "existing regular file",
+		example:    "/etc/krb5.keytab",
+		Value:      newStringValue("", &c.Keytab),
+	}, groupName(name, "KEYTAB"), usage+" (keytab path)", opts...)
+	principalVar := v.define(checkedValue{
+		fn:         isNonEmpty,
+		constraint: "non-empty string",
+		example:    "HTTP/host.example.com@EXAMPLE.COM",
+		Value:      newStringValue("", &c.Principal),
+	}, groupName(name, "PRINCIPAL"), usage+" (principal)", opts...)
+
+	v.Validate(func() error {
+		if i := strings.LastIndex(c.Principal, "@"); i >= 0 && c.Principal[i+1:] != c.Realm {
+			return &CheckError{Name: principalVar.Name, Err: fmt.Errorf("realm %q does not match %v %q", c.Principal[i+1:], realmVar.Name, c.Realm)}
+		}
+		return nil
+	})
+	return c
+}
+
+// LDAP defines a group of variables with specified name prefix and usage string which
+// configure a connection to an LDAP directory (see VarSet.LDAP).
+// The return value is the address of an LDAPConfig that stores the values of the variables.
+func LDAP(name, usage string, opts ...Option) *LDAPConfig {
+	return CmdVar.LDAP(name, usage, opts...)
+}
+
+// Kerberos defines a group of variables with specified name prefix and usage string which
+// configure Kerberos authentication (see VarSet.Kerberos).
+// The return value is the address of a KerberosConfig that stores the values of the variables.
+func Kerberos(name, usage string, opts ...Option) *KerberosConfig {
+	return CmdVar.Kerberos(name, usage, opts...)
+}
+
+// PaginationConfig is the configuration for paginating API results.
+type PaginationConfig struct {
+	DefaultPageSize int    // page size used when none is requested
+	MaxPageSize     int    // largest page size which can be requested
+	DefaultSort     string // sort order used when none is requested
+}
+
+// isPositiveInt checks if x is an integer greater than zero.
+func isPositiveInt(x string) error {
+	n, err := strconv.Atoi(x)
+	if err != nil {
+		return errors.New("parsing " + strconv.Quote(x) + ": invalid integer")
+	}
+	if n <= 0 {
+		return errors.New("must be greater than zero")
+	}
+	return nil
+}
+
+// isSortOrder checks if x is a comma separated list of field names, each optionally
+// prefixed by + (ascending) or - (descending).
+func isSortOrder(x string) error {
+	if x == "" {
+		return errors.New("empty sort order")
+	}
+	for _, f := range strings.Split(x, ",") {
+		name := strings.TrimLeft(f, "+-")
+		if len(f)-len(name) > 1 || name == "" || !isAlphanumeric(strings.NewReplacer("_", "", ".", "").Replace(name)) {
+			return fmt.Errorf("invalid sort field %q", f)
+		}
+	}
+	return nil
+}
+
+// Pagination defines a group of variables with specified name prefix and usage string which
+// configure pagination of API results:
+//
+//	NAME_DEFAULT_PAGE_SIZE  page size used when none is requested
+//	NAME_MAX_PAGE_SIZE      largest page size which can be requested
+//	NAME_DEFAULT_SORT       sort order used when none is requested (i.e. "-created,name")
+//
+// If name is empty then the variables are not prefixed (i.e. DEFAULT_PAGE_SIZE).  Parse checks
+// that the page sizes are positive and that the default page size is no larger than the maximum.
+// The return value is the address of a PaginationConfig that stores the values of the variables.
+func (v *VarSet) Pagination(name, usage string, opts ...Option) *PaginationConfig {
+	c := new(PaginationConfig)
+	defaultSizeVar := v.define(checkedValue{
+		fn:         isPositiveInt,
+		schema:     positiveIntSchema,
+		constraint: "positive integer",
+		example:    "20",
+		Value:      newIntValue(0, &c.DefaultPageSize),
+	}, groupName(name, "DEFAULT_PAGE_SIZE"), usage+" (default page size)", opts...)
+	maxSizeVar := v.define(checkedValue{
+		fn:         isPositiveInt,
+		schema:     positiveIntSchema,
+		constraint: "positive integer",
+		example:    "100",
+		Value:      newIntValue(0, &c.MaxPageSize),
+	}, groupName(name, "MAX_PAGE_SIZE"), usage+" (maximum page size)", opts...)
+	v.Var(checkedValue{
+		fn:         isSortOrder,
+		constraint: "comma separated fields, prefixed by + or -",
+		example:    "-created,name",
+		Value:      newStringValue("", &c.DefaultSort),
+	}, groupName(name, "DEFAULT_SORT"), usage+" (default sort order)", opts...)
+
+	v.Validate(func() error {
+		if c.DefaultPageSize > c.MaxPageSize {
+			return &CheckError{Name: defaultSizeVar.Name, Err: fmt.Errorf("%d is greater than %v %d", c.DefaultPageSize, maxSizeVar.Name, c.MaxPageSize)}
+		}
+		return nil
+	})
+	return c
+}
+
+// Pagination defines a group of variables with specified name prefix and usage string which
+// configure pagination of API results (see VarSet.Pagination).
+// The return value is the address of a PaginationConfig that stores the values of the variables.
+func Pagination(name, usage string, opts ...Option) *PaginationConfig {
+	return CmdVar.Pagination(name, usage, opts...)
+}
+
+// LifecycleConfig is the timing configuration for server health checks and
+// graceful shutdown.
+type LifecycleConfig struct {
+	ShutdownTimeout     time.Duration // maximum time to wait for in-flight work on shutdown
+	DrainDelay          time.Duration // time to wait after failing readiness before shutting down
+	HealthcheckInterval time.Duration // interval between health checks
+	ReadinessDelay      time.Duration // time to wait after starting before reporting ready
+}
+
+// isDurationMin returns a function which checks if x is a duration of at least min.
+func isDurationMin(min time.Duration) func(string) error {
+	return func(x string) error {
+		d, err := time.ParseDuration(x)
+		if err != nil {
+			return err
+		}
+		if d < min {
+			return fmt.Errorf("%v is less than %v", d, min)
+		}
+		return nil
+	}
+}
+
+// Lifecycle defines a group of variables with specified name prefix and usage string which
+// configure health check and graceful shutdown timing:
+//
+//	NAME_SHUTDOWN_TIMEOUT      maximum time to wait for in-flight work on shutdown
+//	NAME_DRAIN_DELAY           time to wait after failing readiness before shutting down
+//	NAME_HEALTHCHECK_INTERVAL  interval between health checks
+//	NAME_READINESS_DELAY       time to wait after starting before reporting ready
+//
+// If name is empty then the variables are not prefixed (i.e. SHUTDOWN_TIMEOUT).  Durations
+// must not be negative, the health check interval must be positive, and Parse checks that the
+// drain delay is no longer than the shutdown timeout.
+// The return value is the address of a LifecycleConfig that stores the values of the variables.
+func (v *VarSet) Lifecycle(name, usage string, opts ...Option) *LifecycleConfig {
+	c := new(LifecycleConfig)
+	shutdownVar := v.define(checkedValue{
+		fn:         isDurationMin(0),
+		schema:     nonNegativeDurationSchema,
+		constraint: "non-negative duration",
+		example:    "30s",
+		Value:      newDurationValue(0, &c.ShutdownTimeout),
+	}, groupName(name, "SHUTDOWN_TIMEOUT"), usage+" (shutdown timeout)", opts...)
+	drainVar := v.define(checkedValue{
+		fn:         isDurationMin(0),
+		schema:     nonNegativeDurationSchema,
+		constraint: "non-negative duration",
+		example:    "5s",
+		Value:      newDurationValue(0, &c.DrainDelay),
+	}, groupName(name, "DRAIN_DELAY"), usage+" (drain delay)", opts...)
+	v.Var(checkedValue{
+		fn:         isDurationMin(time.Nanosecond),
+		schema:     positiveDurationSchema,
+		constraint: "positive duration",
+		example:    "10s",
+		Value:      newDurationValue(0, &c.HealthcheckInterval),
+	}, groupName(name, "HEALTHCHECK_INTERVAL"), usage+" (health check interval)", opts...)
+	v.Var(checkedValue{
+		fn:         isDurationMin(0),
+		schema:     nonNegativeDurationSchema,
+		constraint: "non-negative duration",
+		example:    "0s",
+		Value:      newDurationValue(0, &c.ReadinessDelay),
+	}, groupName(name, "READINESS_DELAY"), usage+" (readiness delay)", opts...)
+
+	v.Validate(func() error {
+		if c.DrainDelay > c.ShutdownTimeout {
+			return &CheckError{Name: drainVar.Name, Err: fmt.Errorf("%v is longer than %v %v", c.DrainDelay, shutdownVar.Name, c.ShutdownTimeout)}
+		}
+		return nil
+	})
+	return c
+}
+
+// Lifecycle defines a group of variables with specified name prefix and usage string which
+// configure health check and graceful shutdown timing (see VarSet.Lifecycle).
+// The return value is the address of a LifecycleConfig that stores the values of the variables.
+func Lifecycle(name, usage string, opts ...Option) *LifecycleConfig {
+	return CmdVar.Lifecycle(name, usage, opts...)
+}
@@ -0,0 +1,185 @@
+package env_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"code.sajari.com/env"
+)
+
+func TestLDAP(t *testing.T) {
+	valid := testGetter{
+		"APP_LDAP_URL":           "ldaps://ldap.example.com:636",
+		"APP_LDAP_BIND_DN":       "cn=admin,dc=example,dc=com",
+		"APP_LDAP_BIND_PASSWORD": "secret",
+		"APP_LDAP_BASE_DN":       "dc=example,dc=com",
+		"APP_LDAP_TLS_MODE":      "tls",
+	}
+
+	tests := []struct {
+		name    string
+		set     map[string]string
+		wantErr bool
+	}{
+		{"valid", nil, false},
+		{"anonymous", map[string]string{"APP_LDAP_BIND_DN": "", "APP_LDAP_BIND_PASSWORD": ""}, false},
+		{"starttls", map[string]string{"APP_LDAP_URL": "ldap://ldap.example.com", "APP_LDAP_TLS_MODE": "starttls"}, false},
+
+		{"bad scheme", map[string]string{"APP_LDAP_URL": "http://ldap.example.com"}, true},
+		{"bad tls mode", map[string]string{"APP_LDAP_TLS_MODE": "yes"}, true},
+		{"bad base dn", map[string]string{"APP_LDAP_BASE_DN": "example.com"}, true},
+		{"ldaps without tls", map[string]string{"APP_LDAP_TLS_MODE": "starttls"}, true},
+		{"ldap with tls", map[string]string{"APP_LDAP_URL": "ldap://ldap.example.com"}, true},
+		{"missing password", map[string]string{"APP_LDAP_BIND_PASSWORD": ""}, true},
+		{"missing bind dn", map[string]string{"APP_LDAP_BIND_DN": ""}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tg := testGetter{}
+			for k, v := range valid {
+				tg[k] = v
+			}
+			for k, v := range tt.set {
+				tg[k] = v
+			}
+
+			vs := env.NewVarSet("app")
+			c := vs.LDAP("LDAP", "directory")
+
+			if err := vs.Parse(tg); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && c.URL != tg["APP_LDAP_URL"] {
+				t.Errorf("c.URL = %q, expected %q", c.URL, tg["APP_LDAP_URL"])
+			}
+		})
+	}
+
+	vs := env.NewVarSet("app")
+	c := vs.LDAP("LDAP", "directory")
+	anon := testGetter{"APP_LDAP_URL": "ldap://ldap.example.com", "APP_LDAP_BASE_DN": "dc=example,dc=com", "APP_LDAP_TLS_MODE": "none"}
+	if err := vs.Parse(anon); err != nil || c.BindDN != "" || c.BindPassword != "" {
+		t.Errorf("vs.Parse() = %v with bind DN %q, expected anonymous bind with bind DN and password unset", err, c.BindDN)
+	}
+
+	vs = env.NewVarSet("app")
+	vs.LDAP("LDAP", "directory", env.NoPrefix())
+	err := vs.Parse(testGetter{"LDAP_URL": "ldap://ldap.example.com", "LDAP_BASE_DN": "dc=example,dc=com", "LDAP_TLS_MODE": "none", "LDAP_BIND_DN": "cn=admin"})
+	if err == nil || !strings.Contains(err.Error(), "env LDAP_BIND_PASSWORD:") {
+		t.Errorf("vs.Parse() = %v, expected error naming LDAP_BIND_PASSWORD", err)
+	}
+
+	vs = env.NewVarSet("app")
+	vs.LDAP("LDAP", "directory")
+	vs.Visit(func(x *env.Var) {
+		if secret := x.Name == "APP_LDAP_BIND_PASSWORD"; x.Secret() != secret {
+			t.Errorf("%v: Secret() = %v, expected %v", x.Name, x.Secret(), secret)
+		}
+	})
+}
+
+func TestKerberos(t *testing.T) {
+	keytab, err := ioutil.TempFile("", "Kerberos")
+	if err != nil {
+		t.Fatalf("could not create temporary file: %v", err)
+	}
+	defer keytab.Close()
+	defer os.Remove(keytab.Name())
+
+	tests := []struct {
+		name      string
+		principal string
+		keytab    string
+		wantErr   bool
+	}{
+		{"valid", "HTTP/host.example.com@EXAMPLE.COM", keytab.Name(), false},
+		{"no realm", "HTTP/host.example.com", keytab.Name(), false},
+
+		{"wrong realm", "HTTP/host.example.com@OTHER.COM", keytab.Name(), true},
+		{"missing keytab", "HTTP/host.example.com", "filedoesnotexist.keytab", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			c := vs.Kerberos("KRB", "kerberos")
+
+			tg := testGetter{
+				"KRB_REALM":     "EXAMPLE.COM",
+				"KRB_KEYTAB":    tt.keytab,
+				"KRB_PRINCIPAL": tt.principal,
+			}
+			if err := vs.Parse(tg); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && c.Principal != tt.principal {
+				t.Errorf("c.Principal = %q, expected %q", c.Principal, tt.principal)
+			}
+		})
+	}
+}
+
+func TestPagination(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     testGetter
+		out     env.PaginationConfig
+		wantErr bool
+	}{
+		{"valid", testGetter{"DEFAULT_PAGE_SIZE": "20", "MAX_PAGE_SIZE": "100", "DEFAULT_SORT": "-created,name"}, env.PaginationConfig{20, 100, "-created,name"}, false},
+		{"equal", testGetter{"DEFAULT_PAGE_SIZE": "100", "MAX_PAGE_SIZE": "100", "DEFAULT_SORT": "+id"}, env.PaginationConfig{100, 100, "+id"}, false},
+
+		{"default > max", testGetter{"DEFAULT_PAGE_SIZE": "200", "MAX_PAGE_SIZE": "100", "DEFAULT_SORT": "id"}, env.PaginationConfig{200, 100, "id"}, true},
+		{"zero", testGetter{"DEFAULT_PAGE_SIZE": "0", "MAX_PAGE_SIZE": "100", "DEFAULT_SORT": "id"}, env.PaginationConfig{0, 100, "id"}, true},
+		{"bad sort", testGetter{"DEFAULT_PAGE_SIZE": "20", "MAX_PAGE_SIZE": "100", "DEFAULT_SORT": "--id"}, env.PaginationConfig{20, 100, ""}, true},
+		{"empty sort field", testGetter{"DEFAULT_PAGE_SIZE": "20", "MAX_PAGE_SIZE": "100", "DEFAULT_SORT": "id,"}, env.PaginationConfig{20, 100, ""}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			c := vs.Pagination("", "pagination")
+
+			if err := vs.Parse(tt.env); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if *c != tt.out {
+				t.Errorf(" = %+v, expected %+v", *c, tt.out)
+			}
+		})
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     testGetter
+		out     env.LifecycleConfig
+		wantErr bool
+	}{
+		{"valid", testGetter{"SHUTDOWN_TIMEOUT": "30s", "DRAIN_DELAY": "5s", "HEALTHCHECK_INTERVAL": "10s", "READINESS_DELAY": "0s"}, env.LifecycleConfig{30 * time.Second, 5 * time.Second, 10 * time.Second, 0}, false},
+		{"drain = shutdown", testGetter{"SHUTDOWN_TIMEOUT": "5s", "DRAIN_DELAY": "5s", "HEALTHCHECK_INTERVAL": "1s", "READINESS_DELAY": "1s"}, env.LifecycleConfig{5 * time.Second, 5 * time.Second, time.Second, time.Second}, false},
+
+		{"drain > shutdown", testGetter{"SHUTDOWN_TIMEOUT": "5s", "DRAIN_DELAY": "10s", "HEALTHCHECK_INTERVAL": "1s", "READINESS_DELAY": "0s"}, env.LifecycleConfig{5 * time.Second, 10 * time.Second, time.Second, 0}, true},
+		{"zero interval", testGetter{"SHUTDOWN_TIMEOUT": "5s", "DRAIN_DELAY": "1s", "HEALTHCHECK_INTERVAL": "0s", "READINESS_DELAY": "0s"}, env.LifecycleConfig{5 * time.Second, time.Second, 0, 0}, true},
+		{"negative", testGetter{"SHUTDOWN_TIMEOUT": "-5s", "DRAIN_DELAY": "1s", "HEALTHCHECK_INTERVAL": "1s", "READINESS_DELAY": "0s"}, env.LifecycleConfig{0, time.Second, time.Second, 0}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			c := vs.Lifecycle("", "lifecycle")
+
+			if err := vs.Parse(tt.env); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if *c != tt.out {
+				t.Errorf(" = %+v, expected %+v", *c, tt.out)
+			}
+		})
+	}
+}
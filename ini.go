@@ -0,0 +1,104 @@
+package env
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// INIFile returns a Getter for the values in the INI file at path (see INI).
+func INIFile(path string) (Getter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	g, err := INI(f)
+	if err != nil {
+		return nil, fmt.Errorf("%v:%v", path, err)
+	}
+	return g, nil
+}
+
+// INI returns a Getter for the values in INI syntax read from r.  The key of each value
+// is prefixed by its section and converted in the same way as VarSet names:
+//
+//	; comment
+//	name = a          ; NAME=a (keys before the first section are not prefixed)
+//	[db]
+//	max-conns = 10    ; DB_MAX_CONNS=10
+//	[db.replica]
+//	host: b           ; DB_REPLICA_HOST=b
+//
+// Lines starting with ; or # are comments, and keys are separated from values by = or
+// :.  Values are trimmed, and matching surrounding quotes are removed.
+func INI(r io.Reader) (MapGetter, error) {
+	g := make(MapGetter)
+	section := ""
+
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case line == "" || line[0] == ';' || line[0] == '#':
+			continue
+		case line[0] == '[':
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("%d: unterminated section", n)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				return nil, fmt.Errorf("%d: empty section name", n)
+			}
+			section = suffixName(name)
+			continue
+		}
+
+		i := strings.IndexAny(line, "=:")
+		if i < 0 {
+			return nil, fmt.Errorf("%d: expected key = value", n)
+		}
+		key := strings.TrimSpace(line[:i])
+		if key == "" {
+			return nil, fmt.Errorf("%d: empty key", n)
+		}
+		value, err := iniValue(strings.TrimSpace(line[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("%d: %v", n, err)
+		}
+
+		name := suffixName(key)
+		if section != "" {
+			name = section + "_" + name
+		}
+		g[name] = value
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// iniValue removes a trailing comment or matching quotes from x.
+func iniValue(x string) (string, error) {
+	if x != "" && (x[0] == '"' || x[0] == '\'') {
+		i := strings.IndexByte(x[1:], x[0])
+		if i < 0 {
+			return "", errors.New("unterminated quote")
+		}
+		if rest := strings.TrimSpace(x[i+2:]); rest != "" && rest[0] != ';' && rest[0] != '#' {
+			return "", errors.New("unexpected text after quoted value")
+		}
+		return x[1 : i+1], nil
+	}
+	for _, c := range []string{" ;", " #", "\t;", "\t#"} {
+		if i := strings.Index(x, c); i >= 0 {
+			x = x[:i]
+		}
+	}
+	return strings.TrimSpace(x), nil
+}
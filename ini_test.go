@@ -0,0 +1,74 @@
+package env_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestINI(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		out     env.MapGetter
+		wantErr bool
+	}{
+		{"empty", "", env.MapGetter{}, false},
+		{"global", "name = a\n", env.MapGetter{"NAME": "a"}, false},
+		{"sections", "[db]\nmax-conns = 10\n[db.replica]\nhost: b\n", env.MapGetter{"DB_MAX_CONNS": "10", "DB_REPLICA_HOST": "b"}, false},
+		{"comments", "; c\n# c\n[svc] \nname = a b ; c\nurl = http://x#y\n", env.MapGetter{"SVC_NAME": "a b", "SVC_URL": "http://x#y"}, false},
+		{"quotes", "a = \"x ; y\"\nb = 'z' ; c\nc =\n", env.MapGetter{"A": "x ; y", "B": "z", "C": ""}, false},
+
+		{"no value", "[db]\nhost\n", nil, true},
+		{"bad section", "[db\n", nil, true},
+		{"empty section", "[]\n", nil, true},
+		{"empty key", "= x\n", nil, true},
+		{"unterminated quote", "a = \"x\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := env.INI(strings.NewReader(tt.in))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("env.INI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(g, tt.out) {
+				t.Errorf("env.INI() = %q, expected %q", g, tt.out)
+			}
+		})
+	}
+}
+
+func TestINIFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.ini")
+	if err := ioutil.WriteFile(path, []byte("[svc]\nworkers = 4\n[other]\nbad\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.INIFile(path); err == nil || !strings.Contains(err.Error(), "config.ini:4:") {
+		t.Errorf("got error %v, expected error on line 4", err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("[svc]\nworkers = 4\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	g, err := env.INIFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error from INIFile: %v", err)
+	}
+	vs := env.NewVarSet("svc")
+	workers := vs.Int("WORKERS", "ini test")
+	if err := vs.Parse(g); err != nil || *workers != 4 {
+		t.Errorf("got (%d, %v), expected (4, nil)", *workers, err)
+	}
+}
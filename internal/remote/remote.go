@@ -0,0 +1,146 @@
+// Package remote provides helpers shared by the Getters in the connect
+// subpackages.
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GetJSON sends a GET request to url with the given headers using client (or
+// http.DefaultClient if nil), and decodes the JSON response into v.  Responses
+// with a status other than 2xx are returned as errors.
+func GetJSON(ctx context.Context, client *http.Client, url string, header http.Header, v interface{}) error {
+	return DoJSON(ctx, client, http.MethodGet, url, header, nil, v)
+}
+
+// DoJSON sends a request to url with the given method, headers and body using client
+// (or http.DefaultClient if nil), and decodes the JSON response into v.  Responses with
+// a status other than 2xx are returned as a *StatusError.
+func DoJSON(ctx context.Context, client *http.Client, method, url string, header http.Header, body io.Reader, v interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{
+			Method:     method,
+			URL:        req.URL.Redacted(),
+			Status:     resp.Status,
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(b)),
+		}
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// StatusError is returned by DoJSON for responses with a status other than 2xx.
+type StatusError struct {
+	Method     string
+	URL        string // request URL, with any password redacted
+	Status     string
+	StatusCode int
+	Body       string // start of the response body
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%v %v: %v: %v", e.Method, e.URL, e.Status, e.Body)
+}
+
+// IsNotFound reports whether err is a StatusError for a 404 Not Found response.
+func IsNotFound(err error) bool {
+	e, ok := err.(*StatusError)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// Snapshot is a set of values which is loaded on first use.
+type Snapshot struct {
+	mu      sync.Mutex
+	values  map[string]string
+	expires time.Time // zero if values do not expire
+}
+
+// Get returns the value of name, calling load to retrieve all values if they have
+// not yet been loaded successfully.
+func (s *Snapshot) Get(ctx context.Context, name string, load func(context.Context) (map[string]string, error)) (string, bool, error) {
+	return s.GetTTL(ctx, name, time.Time{}, func(ctx context.Context) (map[string]string, time.Duration, error) {
+		values, err := load(ctx)
+		return values, 0, err
+	})
+}
+
+// GetTTL returns the value of name, calling load to retrieve all values if they have
+// not yet been loaded successfully or have expired at now.  Values expire after the
+// duration returned by load, or never if it is zero.
+func (s *Snapshot) GetTTL(ctx context.Context, name string, now time.Time, load func(context.Context) (map[string]string, time.Duration, error)) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.values != nil && !s.expires.IsZero() && !now.Before(s.expires) {
+		s.values = nil
+	}
+	if s.values == nil {
+		values, ttl, err := load(ctx)
+		if err != nil {
+			return "", false, err
+		}
+		if values == nil {
+			values = make(map[string]string)
+		}
+		s.values = values
+		s.expires = time.Time{}
+		if ttl > 0 {
+			s.expires = now.Add(ttl)
+		}
+	}
+	z, ok := s.values[name]
+	return z, ok, nil
+}
+
+// Reset discards the loaded values, so that they are loaded again on next use.
+func (s *Snapshot) Reset() {
+	s.mu.Lock()
+	s.values = nil
+	s.mu.Unlock()
+}
+
+// VarName converts a key from a remote store into a variable name in the same way
+// as VarSet names: letters are upper-cased, digits are kept, and other characters
+// (i.e. "/", "." or "-") become "_".
+func VarName(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z':
+			return r - 'a' + 'A'
+		case 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		}
+		return '_'
+	}, key)
+}
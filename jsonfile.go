@@ -0,0 +1,97 @@
+package env
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// JSONFile returns a Getter for the values in the JSON document in the file at path
+// (see JSON).
+func JSONFile(path string) (Getter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	g, err := JSON(f)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	return g, nil
+}
+
+// JSON returns a Getter for the values in the JSON object read from r, flattened into
+// variable names.  Keys of nested objects are joined with underscores and converted in
+// the same way as VarSet names (upper-cased, other characters replaced by underscores):
+//
+//	{"db": {"max-conns": 10, "hosts": ["a", "b"]}, "debug": true}
+//
+// gives DB_MAX_CONNS=10, DB_HOSTS=a,b and DEBUG=true.  Arrays of numbers, strings and
+// booleans are joined with commas; elements of other arrays are named by their index
+// (i.e. SERVERS_0_ADDR).  Null values are treated as unset.
+func JSON(r io.Reader) (MapGetter, error) {
+	d := json.NewDecoder(r)
+	d.UseNumber()
+
+	var doc map[string]interface{}
+	if err := d.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after JSON object")
+	}
+
+	g := make(MapGetter)
+	flattenJSON(g, "", doc)
+	return g, nil
+}
+
+// flattenJSON adds the values in x to g, with names prefixed by name.
+func flattenJSON(g MapGetter, name string, x interface{}) {
+	switch x := x.(type) {
+	case map[string]interface{}:
+		for k, v := range x {
+			key := suffixName(k)
+			if name != "" {
+				key = name + "_" + key
+			}
+			flattenJSON(g, key, v)
+		}
+	case []interface{}:
+		items := make([]string, 0, len(x))
+		for _, v := range x {
+			s, ok := jsonScalar(v)
+			if !ok {
+				for i, v := range x {
+					flattenJSON(g, name+"_"+strconv.Itoa(i), v)
+				}
+				return
+			}
+			items = append(items, s)
+		}
+		g[name] = strings.Join(items, ",")
+	default:
+		if s, ok := jsonScalar(x); ok {
+			g[name] = s
+		}
+	}
+}
+
+// jsonScalar returns the string form of a JSON number, string or boolean.
+func jsonScalar(x interface{}) (string, bool) {
+	switch x := x.(type) {
+	case string:
+		return x, true
+	case json.Number:
+		return x.String(), true
+	case bool:
+		return strconv.FormatBool(x), true
+	}
+	return "", false
+}
@@ -0,0 +1,75 @@
+package env_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		out     env.MapGetter
+		wantErr bool
+	}{
+		{"empty", `{}`, env.MapGetter{}, false},
+		{"scalars", `{"name": "a", "workers": 4, "ratio": 0.5, "debug": true, "none": null}`, env.MapGetter{"NAME": "a", "WORKERS": "4", "RATIO": "0.5", "DEBUG": "true"}, false},
+		{"nested", `{"db": {"max-conns": 10, "tls": {"enabled": false}}}`, env.MapGetter{"DB_MAX_CONNS": "10", "DB_TLS_ENABLED": "false"}, false},
+		{"list", `{"hosts": ["a", "b"], "empty": []}`, env.MapGetter{"HOSTS": "a,b", "EMPTY": ""}, false},
+		{"objects", `{"servers": [{"addr": "a:1"}, {"addr": "b:2"}]}`, env.MapGetter{"SERVERS_0_ADDR": "a:1", "SERVERS_1_ADDR": "b:2"}, false},
+		{"big number", `{"id": 12345678901234567890}`, env.MapGetter{"ID": "12345678901234567890"}, false},
+
+		{"not object", `[1, 2]`, nil, true},
+		{"invalid", `{"a": }`, nil, true},
+		{"trailing", `{} {}`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := env.JSON(strings.NewReader(tt.in))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("env.JSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(g, tt.out) {
+				t.Errorf("env.JSON() = %q, expected %q", g, tt.out)
+			}
+		})
+	}
+}
+
+func TestJSONFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(path, []byte(`{"svc": {"workers": 4, "hosts": ["a", "b"]}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := env.JSONFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error from JSONFile: %v", err)
+	}
+	vs := env.NewVarSet("svc")
+	workers := vs.Int("WORKERS", "json test")
+	hosts := vs.String("HOSTS", "json test")
+	if err := vs.Parse(g); err != nil {
+		t.Errorf("unexpected error from Parse: %v", err)
+	}
+	if *workers != 4 || *hosts != "a,b" {
+		t.Errorf("got (%d, %q), expected (4, \"a,b\")", *workers, *hosts)
+	}
+
+	if _, err := env.JSONFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+}
@@ -0,0 +1,130 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// Patterns of the strings accepted by the basic Values, in the ECMA 262 syntax used by
+// JSON Schema.
+const (
+	decimalPattern  = `^[+-]?[0-9]+$`        // strconv.Atoi
+	intPattern      = `^[+-]?` + basePattern // strconv.ParseInt with base 0
+	uintPattern     = `^\+?` + basePattern   // strconv.ParseUint with base 0
+	basePattern     = `(0[xX](_?[0-9a-fA-F])+|0[bB](_?[01])+|0[oO](_?[0-7])+|0(_?[0-7])*|[1-9](_?[0-9])*)$`
+	durationPattern = `^[-+]?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$`
+)
+
+// boolValues are the strings accepted by strconv.ParseBool.
+var boolValues = []string{"1", "t", "T", "TRUE", "true", "True", "0", "f", "F", "FALSE", "false", "False"}
+
+var (
+	positiveIntSchema         = map[string]interface{}{"pattern": `^\+?0*[1-9][0-9]*$`}
+	nonNegativeDurationSchema = map[string]interface{}{"pattern": `^\+?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$`}
+
+	// At least one element of a positive duration has a non-zero digit.
+	positiveDurationSchema = map[string]interface{}{"pattern": `^\+?((([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))*` +
+		`([0-9]*[1-9][0-9]*(\.[0-9]*)?|[0-9]*\.[0-9]*[1-9][0-9]*)(ns|us|µs|μs|ms|s|m|h)` +
+		`(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))*)$`}
+)
+
+// enumSchema returns the JSON Schema keywords for a check that a value is one of values.
+func enumSchema(values ...string) map[string]interface{} {
+	return map[string]interface{}{"enum": values}
+}
+
+// valueSchema returns the JSON Schema for the strings accepted by v, as far as it is
+// known.
+func valueSchema(v Value) map[string]interface{} {
+	s := map[string]interface{}{"type": "string"}
+
+	cv, checked := v.(checkedValue)
+	if checked {
+		v = cv.Value
+	}
+	switch v := v.(type) {
+	case *intValue:
+		s["pattern"] = decimalPattern
+	case *boolValue:
+		s["enum"] = boolValues
+	case *durationValue:
+		s["pattern"] = durationPattern
+	case *kindValue:
+		switch v.v.Kind() {
+		case reflect.Bool:
+			s["enum"] = boolValues
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			s["pattern"] = intPattern
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			s["pattern"] = uintPattern
+		}
+	}
+
+	for k, x := range cv.schema {
+		if k == "enum" || k == "pattern" {
+			delete(s, "enum")
+			delete(s, "pattern")
+		}
+		s[k] = x
+	}
+	return s
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) for the environment accepted by the
+// set, so that external tools can validate an environment before it is deployed.  The
+// environment is described as an object with a string property for each variable,
+// constrained by enum, pattern or format where the validation of the variable is
+// known.  Each property is described by its usage and constraint (see Describe), with
+// examples and defaults.  Secret variables are marked writeOnly, and their defaults are
+// omitted.  Variables which must be set are listed as required.
+//
+// The schema does not include checks made by Validate or by Values of other packages,
+// so an environment which matches it may still fail to parse.
+func (v *VarSet) JSONSchema() ([]byte, error) {
+	properties := make(map[string]interface{})
+	required := []string{}
+	v.Visit(func(x *Var) {
+		p := valueSchema(x.Value)
+		constraint, example := Describe(x.Value)
+		description := x.Usage
+		if constraint != "" {
+			description += " (" + constraint + ")"
+		}
+		if description != "" {
+			p["description"] = description
+		}
+		if example != "" {
+			p["examples"] = []string{example}
+		}
+		if x.def != nil && !x.secret {
+			p["default"] = x.def()
+		}
+		if x.secret {
+			p["writeOnly"] = true
+		}
+		properties[x.Name] = p
+		if !x.optional && x.def == nil {
+			required = append(required, x.Name)
+		}
+	})
+
+	schema := map[string]interface{}{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+	if v.name != "" {
+		schema["title"] = v.name
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(schema); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
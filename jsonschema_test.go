@@ -0,0 +1,120 @@
+package env_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestJSONSchema(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	vs.Int("WORKERS", "number of workers", env.Default("4"))
+	vs.Hostname("HOST", "service host")
+	vs.String("TOKEN", "api token", env.Secret(), env.Default("abc"))
+	vs.OutputFormat("FORMAT", "output format", []string{"json", "text"})
+	vs.Bool("DEBUG", "debug logging", env.Optional())
+
+	b, err := vs.JSONSchema()
+	if err != nil {
+		t.Fatalf("unexpected error from JSONSchema: %v", err)
+	}
+	var schema struct {
+		Title      string                            `json:"title"`
+		Type       string                            `json:"type"`
+		Properties map[string]map[string]interface{} `json:"properties"`
+		Required   []string                          `json:"required"`
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatalf("unexpected error reading schema: %v\n%s", err, b)
+	}
+
+	if schema.Title != "svc" || schema.Type != "object" {
+		t.Errorf("got title %q, type %q: expected \"svc\", \"object\"", schema.Title, schema.Type)
+	}
+	if expected := []string{"SVC_HOST", "SVC_FORMAT"}; !reflect.DeepEqual(schema.Required, expected) {
+		t.Errorf("got required %v, expected %v", schema.Required, expected)
+	}
+
+	workers := schema.Properties["SVC_WORKERS"]
+	if workers["default"] != "4" || workers["type"] != "string" || workers["pattern"] == nil {
+		t.Errorf("unexpected schema for SVC_WORKERS: %v", workers)
+	}
+	if workers["description"] != "number of workers (integer)" {
+		t.Errorf("got description %q for SVC_WORKERS", workers["description"])
+	}
+	if host := schema.Properties["SVC_HOST"]; host["format"] != "hostname" {
+		t.Errorf("expected hostname format for SVC_HOST, got %v", host)
+	}
+	token := schema.Properties["SVC_TOKEN"]
+	if token["writeOnly"] != true || token["default"] != nil {
+		t.Errorf("expected secret SVC_TOKEN to be writeOnly without default, got %v", token)
+	}
+	if format := schema.Properties["SVC_FORMAT"]; !reflect.DeepEqual(format["enum"], []interface{}{"json", "text"}) {
+		t.Errorf("got enum %v for SVC_FORMAT, expected [json text]", format["enum"])
+	}
+	if debug := schema.Properties["SVC_DEBUG"]; debug["enum"] == nil {
+		t.Errorf("expected boolean enum for SVC_DEBUG, got %v", debug)
+	}
+}
+
+func TestJSONSchemaPatterns(t *testing.T) {
+	vs := env.NewVarSet("")
+	vs.Int("WORKERS", "number of workers")
+	vs.Lifecycle("", "lifecycle")
+	var c struct {
+		Limit int64 `env:"LIMIT"`
+	}
+	if err := env.Bind(vs, &c); err != nil {
+		t.Fatalf("unexpected error from Bind: %v", err)
+	}
+
+	b, err := vs.JSONSchema()
+	if err != nil {
+		t.Fatalf("unexpected error from JSONSchema: %v", err)
+	}
+	var schema struct {
+		Properties map[string]struct {
+			Pattern string `json:"pattern"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatalf("unexpected error reading schema: %v\n%s", err, b)
+	}
+
+	tests := []struct {
+		name  string
+		in    string
+		match bool
+	}{
+		{"WORKERS", "42", true},
+		{"WORKERS", "-7", true},
+		{"WORKERS", "0x1f", false},
+		{"WORKERS", "1_000", false},
+		{"LIMIT", "0x1f", true},
+		{"LIMIT", "-0o17", true},
+		{"LIMIT", "1_000", true},
+		{"LIMIT", "1__000", false},
+		{"LIMIT", "09", false},
+		{"HEALTHCHECK_INTERVAL", "10s", true},
+		{"HEALTHCHECK_INTERVAL", "0s1ms", true},
+		{"HEALTHCHECK_INTERVAL", "1.5h", true},
+		{"HEALTHCHECK_INTERVAL", "0", false},
+		{"HEALTHCHECK_INTERVAL", "0s", false},
+		{"HEALTHCHECK_INTERVAL", "0.0ms", false},
+		{"HEALTHCHECK_INTERVAL", "-1s", false},
+		{"SHUTDOWN_TIMEOUT", "0", true},
+		{"SHUTDOWN_TIMEOUT", "-1s", false},
+	}
+	for _, tt := range tests {
+		re, err := regexp.Compile(schema.Properties[tt.name].Pattern)
+		if err != nil {
+			t.Fatalf("%v: invalid pattern: %v", tt.name, err)
+		}
+		if got := re.MatchString(tt.in); got != tt.match {
+			t.Errorf("%v: pattern matches %q = %v, expected %v", tt.name, tt.in, got, tt.match)
+		}
+	}
+}
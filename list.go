@@ -0,0 +1,291 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// splitList splits a comma separated list, trimming space around each item
+// and dropping empty items.
+func splitList(x string) []string {
+	var items []string
+	for _, s := range strings.Split(x, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			items = append(items, s)
+		}
+	}
+	return items
+}
+
+// listValue is a comma separated list of strings, where each item is validated
+// by fn.
+type listValue struct {
+	p  *[]string
+	fn func(string) error
+
+	constraint string
+	example    string
+}
+
+func (v *listValue) Set(x string) error {
+	items := splitList(x)
+	if len(items) == 0 {
+		return errors.New("empty list")
+	}
+	for _, s := range items {
+		if err := v.fn(s); err != nil {
+			return err
+		}
+	}
+	*v.p = items
+	return nil
+}
+
+func (v *listValue) String() string {
+	return strings.Join(*v.p, ",")
+}
+
+func (v *listValue) typeName() string { return "list" }
+
+func (v *listValue) Constraint() string { return v.constraint }
+func (v *listValue) Example() string    { return v.example }
+
+// isOrigin checks if x is a web origin of the form scheme://host[:port].  The host may
+// start with a "*." wildcard to match subdomains, and "*" matches all origins.
+func isOrigin(x string) error {
+	if x == "*" {
+		return nil
+	}
+	u, err := url.Parse(strings.Replace(x, "://*.", "://wildcard.", 1))
+	if err != nil {
+		return err
+	}
+	switch {
+	case u.Scheme == "" || u.Host == "":
+		return fmt.Errorf("origin %q must be of the form scheme://host[:port]", x)
+	case u.User != nil || u.Path != "" || u.RawQuery != "" || u.Fragment != "" || strings.HasSuffix(x, "?") || strings.HasSuffix(x, "#"):
+		return fmt.Errorf("origin %q must not contain user info, path, query or fragment", x)
+	}
+	return nil
+}
+
+// CORSOrigins defines a []string variable with specified name and usage string holding a
+// comma separated list of origins allowed for cross-origin requests.  Each origin must be
+// of the form scheme://host[:port] (i.e. "https://example.com"), optionally with a "*."
+// wildcard host prefix, or "*" to allow all origins.
+// The return value is the address of a []string variable that stores the value of the variable.
+func (v *VarSet) CORSOrigins(name, usage string, opts ...Option) *[]string {
+	p := new([]string)
+	v.Var(&listValue{
+		p:          p,
+		fn:         isOrigin,
+		constraint: "comma separated origins (scheme://host[:port]) or *",
+		example:    "https://example.com,https://*.example.com",
+	}, name, usage, opts...)
+	return p
+}
+
+// cspDirectives are the directives defined by Content Security Policy Level 3.
+var cspDirectives = map[string]bool{
+	"base-uri":                  true,
+	"block-all-mixed-content":   true,
+	"child-src":                 true,
+	"connect-src":               true,
+	"default-src":               true,
+	"fenced-frame-src":          true,
+	"font-src":                  true,
+	"form-action":               true,
+	"frame-ancestors":           true,
+	"frame-src":                 true,
+	"img-src":                   true,
+	"manifest-src":              true,
+	"media-src":                 true,
+	"navigate-to":               true,
+	"object-src":                true,
+	"plugin-types":              true,
+	"prefetch-src":              true,
+	"report-to":                 true,
+	"report-uri":                true,
+	"require-sri-for":           true,
+	"require-trusted-types-for": true,
+	"sandbox":                   true,
+	"script-src":                true,
+	"script-src-attr":           true,
+	"script-src-elem":           true,
+	"style-src":                 true,
+	"style-src-attr":            true,
+	"style-src-elem":            true,
+	"trusted-types":             true,
+	"upgrade-insecure-requests": true,
+	"webrtc":                    true,
+	"worker-src":                true,
+}
+
+// cspKeywords are source expressions which must be single quoted.
+var cspKeywords = map[string]bool{
+	"self":             true,
+	"none":             true,
+	"unsafe-inline":    true,
+	"unsafe-eval":      true,
+	"strict-dynamic":   true,
+	"unsafe-hashes":    true,
+	"report-sample":    true,
+	"wasm-unsafe-eval": true,
+}
+
+// isCSP checks if x is a well formed Content-Security-Policy header value: a semicolon
+// separated list of known directives, each appearing at most once, whose keyword sources
+// are quoted.
+func isCSP(x string) error {
+	seen := make(map[string]bool)
+	for _, d := range strings.Split(x, ";") {
+		fields := strings.Fields(d)
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.ToLower(fields[0])
+		if !cspDirectives[name] {
+			return fmt.Errorf("unknown directive %q", fields[0])
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate directive %q", name)
+		}
+		seen[name] = true
+
+		for _, src := range fields[1:] {
+			if strings.ContainsAny(src, ",") {
+				return fmt.Errorf("invalid source %q in %v, use spaces to separate sources", src, name)
+			}
+			if cspKeywords[strings.ToLower(src)] {
+				return fmt.Errorf("keyword %q in %v must be quoted: '%v'", src, name, src)
+			}
+		}
+	}
+	if len(seen) == 0 {
+		return errors.New("empty policy")
+	}
+	return nil
+}
+
+// CSP defines a string variable with specified name and usage string validated as a
+// Content-Security-Policy header value (i.e. "default-src 'self'; img-src *").
+// The return value is the address of a string variable that stores the value of the variable.
+func (v *VarSet) CSP(name, usage string, opts ...Option) *string {
+	p := new(string)
+	v.Var(checkedValue{
+		fn:         isCSP,
+		constraint: "Content-Security-Policy",
+		example:    "default-src 'self'; img-src *",
+		Value:      newStringValue("", p),
+	}, name, usage, opts...)
+	return p
+}
+
+// isMIMEType checks if x is a media type of the form type/subtype (i.e. "image/png"),
+// where subtype, or both type and subtype, may be the wildcard "*".
+func isMIMEType(x string) error {
+	i := strings.Index(x, "/")
+	if i < 0 {
+		return fmt.Errorf("MIME type %q must be of the form type/subtype", x)
+	}
+	typ, sub := x[:i], x[i+1:]
+	if typ == "*" && sub != "*" {
+		return fmt.Errorf("MIME type %q must not have a wildcard type with a subtype", x)
+	}
+	for _, name := range []string{typ, sub} {
+		if name == "*" {
+			continue
+		}
+		if err := isMIMEName(name); err != nil {
+			return fmt.Errorf("MIME type %q: %v", x, err)
+		}
+	}
+	return nil
+}
+
+// isMIMEName checks x is a restricted-name as defined in RFC 6838.
+func isMIMEName(x string) error {
+	if x == "" || len(x) > 127 {
+		return errors.New("name must be 1 to 127 characters")
+	}
+	for i, r := range x {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		case i > 0 && strings.ContainsRune("!#$&-^_.+", r):
+		default:
+			return fmt.Errorf("invalid character %q", r)
+		}
+	}
+	return nil
+}
+
+// isExtension checks if x is a file extension with a leading dot (i.e. ".jpg" or ".tar.gz").
+func isExtension(x string) error {
+	if !strings.HasPrefix(x, ".") {
+		return fmt.Errorf("extension %q must start with a dot", x)
+	}
+	for _, part := range strings.Split(x[1:], ".") {
+		if !isAlphanumeric(strings.NewReplacer("-", "", "_", "", "+", "").Replace(part)) {
+			return fmt.Errorf("invalid extension %q", x)
+		}
+	}
+	return nil
+}
+
+// MIMETypes defines a []string variable with specified name and usage string holding a
+// comma separated list of MIME types (i.e. "image/png,image/*").
+// The return value is the address of a []string variable that stores the value of the variable.
+func (v *VarSet) MIMETypes(name, usage string, opts ...Option) *[]string {
+	p := new([]string)
+	v.Var(&listValue{
+		p:          p,
+		fn:         isMIMEType,
+		constraint: "comma separated MIME types (type/subtype)",
+		example:    "image/png,image/jpeg,application/pdf",
+	}, name, usage, opts...)
+	return p
+}
+
+// Extensions defines a []string variable with specified name and usage string holding a
+// comma separated list of file extensions, each with a leading dot (i.e. ".jpg,.png").
+// The return value is the address of a []string variable that stores the value of the variable.
+func (v *VarSet) Extensions(name, usage string, opts ...Option) *[]string {
+	p := new([]string)
+	v.Var(&listValue{
+		p:          p,
+		fn:         isExtension,
+		constraint: "comma separated file extensions (.ext)",
+		example:    ".jpg,.png,.pdf",
+	}, name, usage, opts...)
+	return p
+}
+
+// CORSOrigins defines a []string variable with specified name and usage string holding a
+// comma separated list of origins allowed for cross-origin requests (see VarSet.CORSOrigins).
+// The return value is the address of a []string variable that stores the value of the variable.
+func CORSOrigins(name, usage string, opts ...Option) *[]string {
+	return CmdVar.CORSOrigins(name, usage, opts...)
+}
+
+// CSP defines a string variable with specified name and usage string validated as a
+// Content-Security-Policy header value.
+// The return value is the address of a string variable that stores the value of the variable.
+func CSP(name, usage string, opts ...Option) *string {
+	return CmdVar.CSP(name, usage, opts...)
+}
+
+// MIMETypes defines a []string variable with specified name and usage string holding a
+// comma separated list of MIME types.
+// The return value is the address of a []string variable that stores the value of the variable.
+func MIMETypes(name, usage string, opts ...Option) *[]string {
+	return CmdVar.MIMETypes(name, usage, opts...)
+}
+
+// Extensions defines a []string variable with specified name and usage string holding a
+// comma separated list of file extensions.
+// The return value is the address of a []string variable that stores the value of the variable.
+func Extensions(name, usage string, opts ...Option) *[]string {
+	return CmdVar.Extensions(name, usage, opts...)
+}
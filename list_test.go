@@ -0,0 +1,151 @@
+package env_test
+
+import (
+	"reflect"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestCORSOrigins(t *testing.T) {
+	tests := []struct {
+		in      string
+		out     []string
+		wantErr bool
+	}{
+		// Valid
+		{"*", []string{"*"}, false},
+		{"https://example.com", []string{"https://example.com"}, false},
+		{"https://example.com, http://localhost:3000", []string{"https://example.com", "http://localhost:3000"}, false},
+		{"https://*.example.com,", []string{"https://*.example.com"}, false},
+
+		// Invalid
+		{"", nil, true},
+		{",", nil, true},
+		{"example.com", nil, true},
+		{"https://example.com/", nil, true},
+		{"https://example.com/path", nil, true},
+		{"https://example.com?q", nil, true},
+		{"https://user@example.com", nil, true},
+		{"https://example.com,ftp//x", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			origins := vs.CORSOrigins("ORIGINS", "cors test")
+
+			if err := vs.Parse(testGetter{"ORIGINS": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(*origins, tt.out) {
+				t.Errorf(" = %q, expected %q", *origins, tt.out)
+			}
+		})
+	}
+}
+
+func TestCSP(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		// Valid
+		{"default-src 'self'", false},
+		{"default-src 'self'; img-src * data:; script-src 'self' https://cdn.example.com;", false},
+		{"upgrade-insecure-requests", false},
+		{"Default-Src 'none'", false},
+
+		// Invalid
+		{"", true},
+		{";", true},
+		{"default-src self", true},
+		{"script-src 'self' unsafe-inline", true},
+		{"default-src 'self'; default-src 'none'", true},
+		{"defualt-src 'self'", true},
+		{"img-src https://a.com,https://b.com", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			vs.CSP("CSP", "csp test")
+
+			if err := vs.Parse(testGetter{"CSP": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMIMETypes(t *testing.T) {
+	tests := []struct {
+		in      string
+		out     []string
+		wantErr bool
+	}{
+		// Valid
+		{"image/png", []string{"image/png"}, false},
+		{"image/png, image/*,application/vnd.ms-excel", []string{"image/png", "image/*", "application/vnd.ms-excel"}, false},
+		{"*/*", []string{"*/*"}, false},
+		{"application/ld+json", []string{"application/ld+json"}, false},
+
+		// Invalid
+		{"", nil, true},
+		{"image", nil, true},
+		{"image/", nil, true},
+		{"/png", nil, true},
+		{"*/png", nil, true},
+		{"text/plain; charset=utf-8", nil, true},
+		{"image/png,image/.png", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			types := vs.MIMETypes("TYPES", "mime types test")
+
+			if err := vs.Parse(testGetter{"TYPES": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(*types, tt.out) {
+				t.Errorf(" = %q, expected %q", *types, tt.out)
+			}
+		})
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	tests := []struct {
+		in      string
+		out     []string
+		wantErr bool
+	}{
+		// Valid
+		{".jpg", []string{".jpg"}, false},
+		{".jpg, .png,.tar.gz", []string{".jpg", ".png", ".tar.gz"}, false},
+		{".c++,.tar-gz", []string{".c++", ".tar-gz"}, false},
+
+		// Invalid
+		{"", nil, true},
+		{"jpg", nil, true},
+		{".", nil, true},
+		{".tar.", nil, true},
+		{".jp g", nil, true},
+		{"../etc", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			exts := vs.Extensions("EXTS", "extensions test")
+
+			if err := vs.Parse(testGetter{"EXTS": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(*exts, tt.out) {
+				t.Errorf(" = %q, expected %q", *exts, tt.out)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// namespace is a variable name prefix reserved by a set.
+type namespace struct {
+	prefix string
+	owner  *VarSet
+}
+
+var namespaces struct {
+	sync.Mutex
+	reserved []namespace
+}
+
+// ReserveNamespace reserves a variable name prefix (i.e. "OTEL_") for the variables of
+// the set and its sub-sets, typically from the init function of the package which
+// defines the set.  This prevents two packages from interpreting the same variables
+// differently: Var panics if it defines a variable in a namespace reserved by another
+// set, and AddVarSet and ParseAll return an error for any such variable.
+//
+// It returns an error if the prefix overlaps one which is already reserved by another
+// set: that is, if either is a prefix of the other.  Reserving the same prefix again
+// for the same set has no effect.
+func (v *VarSet) ReserveNamespace(prefix string) error {
+	if prefix == "" {
+		return errors.New("env namespace must not be empty")
+	}
+
+	namespaces.Lock()
+	defer namespaces.Unlock()
+
+	for _, ns := range namespaces.reserved {
+		if ns.owner == v && ns.prefix == prefix {
+			return nil
+		}
+		if ns.owner != v && (strings.HasPrefix(prefix, ns.prefix) || strings.HasPrefix(ns.prefix, prefix)) {
+			return fmt.Errorf("env namespace %v conflicts with namespace %v reserved by set %q", prefix, ns.prefix, ns.owner.name)
+		}
+	}
+	namespaces.reserved = append(namespaces.reserved, namespace{prefix: prefix, owner: v})
+	return nil
+}
+
+// ReserveNamespace reserves a variable name prefix for the default set (see
+// VarSet.ReserveNamespace).
+func ReserveNamespace(prefix string) error {
+	return CmdVar.ReserveNamespace(prefix)
+}
+
+// ReservedNamespace returns the reserved namespace containing the variable name, if any.
+func ReservedNamespace(name string) (string, bool) {
+	namespaces.Lock()
+	defer namespaces.Unlock()
+
+	for _, ns := range namespaces.reserved {
+		if strings.HasPrefix(name, ns.prefix) {
+			return ns.prefix, true
+		}
+	}
+	return "", false
+}
+
+// checkNamespace returns an error if name is in a namespace reserved by a set other
+// than v or one of the sets containing v.
+func checkNamespace(v *VarSet, name string) error {
+	namespaces.Lock()
+	defer namespaces.Unlock()
+
+	for _, ns := range namespaces.reserved {
+		if !strings.HasPrefix(name, ns.prefix) {
+			continue
+		}
+		for p := v; p != nil; p = p.parent {
+			if p == ns.owner {
+				return nil
+			}
+		}
+		return fmt.Errorf("env %v is in namespace %v reserved by set %q", name, ns.prefix, ns.owner.name)
+	}
+	return nil
+}
+
+// checkNamespaces returns an error for each variable of v and its sub-sets which is in
+// a namespace reserved by another set.
+func (v *VarSet) checkNamespaces() []error {
+	var errs []error
+	for _, x := range v.vars {
+		if err := checkNamespace(v, x.Name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, s := range v.subs {
+		errs = append(errs, s.checkNamespaces()...)
+	}
+	return errs
+}
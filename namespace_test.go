@@ -0,0 +1,85 @@
+package env_test
+
+import (
+	"strings"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestReserveNamespace(t *testing.T) {
+	otel := env.NewVarSet("testns_otel")
+	aws := env.NewVarSet("testns_aws")
+
+	tests := []struct {
+		vs      *env.VarSet
+		prefix  string
+		wantErr bool
+	}{
+		{otel, "TESTNS_OTEL_", false},
+		{aws, "TESTNS_AWS_", false},
+		{otel, "TESTNS_OTEL_", false},
+		{aws, "TESTNS_OTEL_", true},
+		{aws, "TESTNS_OTEL_EXPORTER_", true},
+		{aws, "TESTNS_", true},
+		{aws, "TESTNS_AWSX_", false},
+		{aws, "", true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.vs.ReserveNamespace(tt.prefix); (err != nil) != tt.wantErr {
+			t.Errorf("%v.ReserveNamespace(%q) = %v, wantErr %v", tt.vs.Name(), tt.prefix, err, tt.wantErr)
+		}
+	}
+
+	if p, ok := env.ReservedNamespace("TESTNS_OTEL_ENDPOINT"); !ok || p != "TESTNS_OTEL_" {
+		t.Errorf("env.ReservedNamespace() = (%q, %v), expected (\"TESTNS_OTEL_\", true)", p, ok)
+	}
+	if p, ok := env.ReservedNamespace("TESTNS_OTHER"); ok {
+		t.Errorf("env.ReservedNamespace() = (%q, %v), expected not reserved", p, ok)
+	}
+}
+
+func TestReserveNamespaceConflict(t *testing.T) {
+	lib := env.NewVarSet("testns_lib")
+	if err := lib.ReserveNamespace("TESTNS_LIB_"); err != nil {
+		t.Fatalf("unexpected error from ReserveNamespace: %v", err)
+	}
+	lib.String("ENDPOINT", "namespace test")
+	lib.Sub("exporter").String("ADDR", "namespace test")
+
+	// Variables of other sets cannot be defined in the namespace.
+	func() {
+		defer func() {
+			if r := recover(); r == nil || !strings.Contains(r.(string), "reserved by set \"testns_lib\"") {
+				t.Errorf("got panic %v, expected namespace conflict", r)
+			}
+		}()
+		env.NewVarSet("").String("TESTNS_LIB_ENDPOINT", "namespace test")
+	}()
+
+	// Nor can variables which were defined before the namespace was reserved.
+	other := env.NewVarSet("testns_other")
+	other.String("ENDPOINT", "namespace test")
+	early := env.NewVarSet("")
+	early.String("TESTNS_OTHER_ENDPOINT", "namespace test")
+	if err := other.ReserveNamespace("TESTNS_OTHER_"); err != nil {
+		t.Fatalf("unexpected error from ReserveNamespace: %v", err)
+	}
+
+	app := env.NewVarSet("")
+	if err := app.AddVarSet(lib); err != nil {
+		t.Errorf("unexpected error adding the owner of a namespace: %v", err)
+	}
+	if err := app.AddVarSet(early); err == nil || !strings.Contains(err.Error(), "TESTNS_OTHER_ENDPOINT is in namespace TESTNS_OTHER_") {
+		t.Errorf("got error %v, expected namespace conflict", err)
+	}
+
+	g := testGetter{"TESTNS_OTHER_ENDPOINT": "x", "TESTNS_LIB_ENDPOINT": "y", "TESTNS_LIB_EXPORTER_ADDR": "z"}
+	if err := env.ParseAll(g, other, early); err == nil || !strings.Contains(err.Error(), "reserved by set \"testns_other\"") {
+		t.Errorf("got error %v, expected namespace conflict", err)
+	}
+	if err := env.ParseAll(g, lib, other); err != nil {
+		t.Errorf("unexpected error from ParseAll: %v", err)
+	}
+}
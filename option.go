@@ -0,0 +1,331 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Option configures a variable when it is defined.
+type Option func(*Var)
+
+// Scope describes how a variable behaves when its variable set is reloaded
+// (see Subsystems.Reload).
+type Scope int
+
+// Scopes which can be applied to variables using WithScope.
+const (
+	// Static variables are only set when first parsed: changes to their
+	// values require a restart and are ignored on reload.  This is the
+	// default.
+	Static Scope = iota
+
+	// Dynamic variables are updated on reload, and can be used immediately.
+	Dynamic
+
+	// Draining variables are updated on reload, but should only be applied
+	// to new work: work which is already underway continues with the value
+	// it started with.
+	Draining
+)
+
+// String implements fmt.Stringer.
+func (s Scope) String() string {
+	switch s {
+	case Static:
+		return "static"
+	case Dynamic:
+		return "dynamic"
+	case Draining:
+		return "draining"
+	}
+	return "unknown"
+}
+
+// WithScope sets the reload scope of a variable.
+func WithScope(s Scope) Option {
+	return func(v *Var) {
+		v.Scope = s
+	}
+}
+
+// Optional marks a variable as optional: if it is unset then its value is left
+// unchanged and parsing does not fail.
+func Optional() Option {
+	return func(v *Var) {
+		v.optional = true
+	}
+}
+
+// Default sets the value used for a variable if it is unset.
+func Default(x string) Option {
+	return DefaultFunc(func() string { return x })
+}
+
+// DefaultFunc sets a function which computes the value used for a variable if it
+// is unset (i.e. from the hostname or number of CPUs).  The function is called each
+// time the variable is parsed and found to be unset, and its result is validated in
+// the same way as a value from the environment.
+func DefaultFunc(fn func() string) Option {
+	return func(v *Var) {
+		v.def = fn
+	}
+}
+
+// FallbackTo sets the name of another variable to look up if a variable is unset.
+// The name is used as given, without the VarSet prefix.  Fallbacks are consulted in
+// the order they are given.
+func FallbackTo(name string) Option {
+	return func(v *Var) {
+		if name != v.Name {
+			v.fallbacks = append(v.fallbacks, name)
+		}
+	}
+}
+
+// Criticality describes how Parse handles a variable which is missing or invalid,
+// allowing services to start in a degraded mode rather than fail over a minor setting.
+type Criticality int
+
+// Criticalities which can be applied to variables using WithCriticality.
+const (
+	// Critical variables cause Parse to fail.  This is the default.
+	Critical Criticality = iota
+
+	// Important variables do not cause Parse to fail, but each problem is reported
+	// as a warning (see OnWarning).  An invalid value is replaced by the default, if
+	// the variable has one.
+	Important
+
+	// Minor variables do not cause Parse to fail, and problems are not reported.
+	// An invalid value is replaced by the default, if the variable has one.
+	Minor
+)
+
+// String implements fmt.Stringer.
+func (c Criticality) String() string {
+	switch c {
+	case Critical:
+		return "critical"
+	case Important:
+		return "important"
+	case Minor:
+		return "minor"
+	}
+	return "unknown"
+}
+
+// WithCriticality sets the criticality of a variable.
+func WithCriticality(c Criticality) Option {
+	return func(v *Var) {
+		v.criticality = c
+	}
+}
+
+// Secret marks a variable as holding a secret, so that its value is redacted from
+// output such as VarSet.WriteDotenv.
+func Secret() Option {
+	return func(v *Var) {
+		v.secret = true
+	}
+}
+
+// NoPrefix defines a variable without the VarSet prefix, for standard variables
+// set by the platform (i.e. PORT, HOME or HTTP_PROXY).
+//
+// The prefix is not applied to aliases (see Alias and Deprecated) either, whether they
+// are given before or after NoPrefix.
+func NoPrefix() Option {
+	return func(v *Var) {
+		if v.prefix == "" {
+			return
+		}
+		p := v.prefix + "_"
+		v.Name = strings.TrimPrefix(v.Name, p)
+		for i, name := range v.fallbacks {
+			if v.isAlias(name) {
+				v.fallbacks[i] = strings.TrimPrefix(name, p)
+			}
+		}
+		for i, name := range v.deprecated {
+			v.deprecated[i] = strings.TrimPrefix(name, p)
+		}
+		v.aliases = nil
+		v.prefix = ""
+	}
+}
+
+// Alias adds an alternate name for a variable, which is looked up if the variable is
+// unset.  Unlike FallbackTo, the VarSet prefix is applied to the alias.  Aliases are
+// consulted in the order they are given, and Var.Source reports which name supplied the
+// value.
+func Alias(name string) Option {
+	return func(v *Var) {
+		n := name
+		if v.prefix != "" {
+			n = v.prefix + "_" + n
+			v.aliases = append(v.aliases, n)
+		}
+		FallbackTo(n)(v)
+	}
+}
+
+// Deprecated adds a deprecated name for a variable, which is looked up if the variable
+// is unset in the same way as an Alias.  If the value is supplied by the deprecated name
+// then Parse emits a warning (see OnWarning) naming the replacement.
+func Deprecated(name string) Option {
+	return func(v *Var) {
+		n := name
+		if v.prefix != "" {
+			n = v.prefix + "_" + n
+			v.aliases = append(v.aliases, n)
+		}
+		FallbackTo(n)(v)
+		v.deprecated = append(v.deprecated, n)
+	}
+}
+
+// ParseOption configures the behaviour of VarSet.Parse.
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	reload        bool // skip Static variables
+	ignoreMissing bool // skip variables which are unset
+	failFast      bool // stop at the first error
+	emptyAsUnset  bool // treat variables set to "" as unset
+
+	discriminator string                     // variable naming the suffix for overrides
+	legacy        func(name string) []string // legacy names for a variable
+
+	onWarning func(string) // called with warnings, nil to write to stderr
+	stdin     *lineReader  // source of values for FromStdin variables
+	onEvent   func(Event)  // called with an Event for each failure
+}
+
+// warn reports a warning which does not cause Parse to fail.
+func (c parseConfig) warn(msg string) {
+	if c.onWarning != nil {
+		c.onWarning(msg)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "warning: %v\n", msg)
+}
+
+// OnWarning sets the function called by Parse with warnings, such as the use of a
+// Deprecated name.  By default warnings are written to stderr.
+func OnWarning(fn func(msg string)) ParseOption {
+	return func(c *parseConfig) {
+		c.onWarning = fn
+	}
+}
+
+// IgnoreMissing makes Parse leave variables which are unset unchanged, as
+// though every variable were Optional.
+func IgnoreMissing() ParseOption {
+	return func(c *parseConfig) {
+		c.ignoreMissing = true
+	}
+}
+
+// FailFast makes Parse return as soon as it encounters an error, rather than
+// collecting the errors from all variables.
+func FailFast() ParseOption {
+	return func(c *parseConfig) {
+		c.failFast = true
+	}
+}
+
+// TreatEmptyAsUnset makes Parse treat variables which are set to the empty
+// string as unset, so that fallbacks and defaults apply to them.
+func TreatEmptyAsUnset() ParseOption {
+	return func(c *parseConfig) {
+		c.emptyAsUnset = true
+	}
+}
+
+// nonEmptyGetter is a Getter which reports variables set to "" as unset.
+type nonEmptyGetter struct {
+	Getter
+}
+
+func (g nonEmptyGetter) Get(x string) (string, bool) {
+	z, ok := g.Getter.Get(x)
+	return z, ok && z != ""
+}
+
+// OverrideBy makes Parse look up NAME_<SUFFIX> before NAME for each variable, where
+// SUFFIX is the value of the variable discriminator (i.e. REGION or CLUSTER), upper-cased
+// and with characters other than letters and digits replaced by underscores.  This lets a
+// single environment carry overrides for several regions or clusters:
+//
+//	REGION=us-east1
+//	SVC_WORKERS=4
+//	SVC_WORKERS_US_EAST1=8
+//
+// The discriminator name is used as given, without the VarSet prefix.  If it is unset
+// or empty then no overrides are consulted.
+func OverrideBy(discriminator string) ParseOption {
+	return func(c *parseConfig) {
+		c.discriminator = discriminator
+	}
+}
+
+// suffixName converts x into a form suitable for use in a variable name.
+func suffixName(x string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z':
+			return r - 'a' + 'A'
+		case 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		}
+		return '_'
+	}, x)
+}
+
+// suffixGetter is a Getter which tries NAME_suffix before NAME, except for the
+// discriminator variable itself.
+type suffixGetter struct {
+	Getter
+	discriminator string
+	suffix        string
+}
+
+func (g suffixGetter) Get(x string) (string, bool) {
+	if x != g.discriminator {
+		if z, ok := g.Getter.Get(x + "_" + g.suffix); ok {
+			return z, true
+		}
+	}
+	return g.Getter.Get(x)
+}
+
+// Legacy makes Parse look up the legacy names returned by fn for a variable if it is
+// unset, for importing deployments which use another naming scheme.  Names are full
+// variable names, including the set prefix, and are tried in order after any aliases
+// and fallbacks of the variable, and Var.Source reports the legacy name which supplied
+// the value.  If the value is
+// supplied by a legacy name then Parse emits a warning (see OnWarning) naming the
+// replacement.
+func Legacy(fn func(name string) []string) ParseOption {
+	return func(c *parseConfig) {
+		c.legacy = fn
+	}
+}
+
+// LegacyTable is like Legacy, but takes a table mapping each legacy name to the name
+// of the variable which replaces it.  Where several legacy names map to the same
+// variable they are tried in sorted order.
+func LegacyTable(m map[string]string) ParseOption {
+	byName := make(map[string][]string)
+	for legacy, name := range m {
+		byName[name] = append(byName[name], legacy)
+	}
+	for _, names := range byName {
+		sort.Strings(names)
+	}
+	return Legacy(func(name string) []string {
+		return byName[name]
+	})
+}
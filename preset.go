@@ -0,0 +1,135 @@
+package env
+
+import (
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// logLevels are the values accepted by the LOG_LEVEL variable of the presets.
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+// WebServiceConfig is the standard configuration of an HTTP service.
+type WebServiceConfig struct {
+	BindAddr        string        // address to serve requests on
+	MetricsAddr     string        // address to serve metrics on
+	LogLevel        string        // one of debug, info, warn, error
+	ShutdownTimeout time.Duration // maximum time to wait for in-flight requests on shutdown
+}
+
+// WebService returns a new variable set with given name (see NewVarSet), defining the
+// standard variables of an HTTP service:
+//
+//	BIND_ADDR         address to serve requests on (default :8080)
+//	METRICS_ADDR      address to serve metrics on (default :9090)
+//	LOG_LEVEL         debug, info, warn or error (default info)
+//	SHUTDOWN_TIMEOUT  maximum time to wait for in-flight requests on shutdown (default 30s)
+//
+// Further variables can be defined on the returned set before it is parsed.
+// The WebServiceConfig stores the values of the variables.
+func WebService(name string) (*VarSet, *WebServiceConfig) {
+	v := NewVarSet(name)
+	c := new(WebServiceConfig)
+	v.Var(checkedValue{
+		fn:         isBindAddr,
+		constraint: "bind address (host:port)",
+		example:    ":8080",
+		Value:      newStringValue("", &c.BindAddr),
+	}, "BIND_ADDR", "address to serve requests on", Default(":8080"))
+	presetMetricsAddr(v, &c.MetricsAddr)
+	presetLogLevel(v, &c.LogLevel)
+	presetShutdownTimeout(v, &c.ShutdownTimeout, "maximum time to wait for in-flight requests on shutdown")
+	return v, c
+}
+
+// WorkerConfig is the standard configuration of a background worker.
+type WorkerConfig struct {
+	MetricsAddr     string        // address to serve metrics on
+	LogLevel        string        // one of debug, info, warn, error
+	ShutdownTimeout time.Duration // maximum time to wait for in-flight jobs on shutdown
+	Concurrency     int           // number of jobs to run at once
+}
+
+// Worker returns a new variable set with given name (see NewVarSet), defining the
+// standard variables of a background worker:
+//
+//	METRICS_ADDR      address to serve metrics on (default :9090)
+//	LOG_LEVEL         debug, info, warn or error (default info)
+//	SHUTDOWN_TIMEOUT  maximum time to wait for in-flight jobs on shutdown (default 30s)
+//	CONCURRENCY       number of jobs to run at once (default the number of CPUs)
+//
+// Further variables can be defined on the returned set before it is parsed.
+// The WorkerConfig stores the values of the variables.
+func Worker(name string) (*VarSet, *WorkerConfig) {
+	v := NewVarSet(name)
+	c := new(WorkerConfig)
+	presetMetricsAddr(v, &c.MetricsAddr)
+	presetLogLevel(v, &c.LogLevel)
+	presetShutdownTimeout(v, &c.ShutdownTimeout, "maximum time to wait for in-flight jobs on shutdown")
+	presetConcurrency(v, &c.Concurrency, "number of jobs to run at once", DefaultFunc(func() string { return strconv.Itoa(runtime.NumCPU()) }))
+	return v, c
+}
+
+// CronJobConfig is the standard configuration of a scheduled job.
+type CronJobConfig struct {
+	LogLevel        string        // one of debug, info, warn, error
+	ShutdownTimeout time.Duration // maximum time to wait for the job to finish when interrupted
+	Concurrency     int           // number of tasks to run at once
+}
+
+// CronJob returns a new variable set with given name (see NewVarSet), defining the
+// standard variables of a scheduled job:
+//
+//	LOG_LEVEL         debug, info, warn or error (default info)
+//	SHUTDOWN_TIMEOUT  maximum time to wait for the job to finish when interrupted (default 30s)
+//	CONCURRENCY       number of tasks to run at once (default 1)
+//
+// Further variables can be defined on the returned set before it is parsed.
+// The CronJobConfig stores the values of the variables.
+func CronJob(name string) (*VarSet, *CronJobConfig) {
+	v := NewVarSet(name)
+	c := new(CronJobConfig)
+	presetLogLevel(v, &c.LogLevel)
+	presetShutdownTimeout(v, &c.ShutdownTimeout, "maximum time to wait for the job to finish when interrupted")
+	presetConcurrency(v, &c.Concurrency, "number of tasks to run at once", Default("1"))
+	return v, c
+}
+
+func presetMetricsAddr(v *VarSet, p *string) {
+	v.Var(checkedValue{
+		fn:         isBindAddr,
+		constraint: "bind address (host:port)",
+		example:    ":9090",
+		Value:      newStringValue("", p),
+	}, "METRICS_ADDR", "address to serve metrics on", Default(":9090"))
+}
+
+func presetLogLevel(v *VarSet, p *string) {
+	v.Var(checkedValue{
+		fn:         isOneOf(logLevels...),
+		schema:     enumSchema(logLevels...),
+		constraint: "one of debug, info, warn, error",
+		example:    "info",
+		Value:      newStringValue("", p),
+	}, "LOG_LEVEL", "minimum level of log messages", Default("info"))
+}
+
+func presetShutdownTimeout(v *VarSet, p *time.Duration, usage string) {
+	v.Var(checkedValue{
+		fn:         isDurationMin(0),
+		schema:     nonNegativeDurationSchema,
+		constraint: "non-negative duration",
+		example:    "30s",
+		Value:      newDurationValue(0, p),
+	}, "SHUTDOWN_TIMEOUT", usage, Default("30s"))
+}
+
+func presetConcurrency(v *VarSet, p *int, usage string, def Option) {
+	v.Var(checkedValue{
+		fn:         isPositiveInt,
+		schema:     positiveIntSchema,
+		constraint: "positive integer",
+		example:    "4",
+		Value:      newIntValue(0, p),
+	}, "CONCURRENCY", usage, def)
+}
@@ -0,0 +1,54 @@
+package env_test
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"code.sajari.com/env"
+)
+
+func TestWebService(t *testing.T) {
+	vs, c := env.WebService("api")
+	if err := vs.Parse(testGetter{"API_BIND_ADDR": ":80", "API_LOG_LEVEL": "debug"}); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+	expected := &env.WebServiceConfig{BindAddr: ":80", MetricsAddr: ":9090", LogLevel: "debug", ShutdownTimeout: 30 * time.Second}
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("got %+v, expected %+v", c, expected)
+	}
+
+	vs, _ = env.WebService("api")
+	if err := vs.Parse(testGetter{"API_LOG_LEVEL": "verbose"}); err == nil {
+		t.Errorf("expected error for invalid log level")
+	}
+}
+
+func TestWorker(t *testing.T) {
+	vs, c := env.Worker("jobs")
+	if err := vs.Parse(testGetter{"JOBS_SHUTDOWN_TIMEOUT": "5m"}); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+	expected := &env.WorkerConfig{MetricsAddr: ":9090", LogLevel: "info", ShutdownTimeout: 5 * time.Minute, Concurrency: runtime.NumCPU()}
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("got %+v, expected %+v", c, expected)
+	}
+
+	vs, _ = env.Worker("jobs")
+	if err := vs.Parse(testGetter{"JOBS_CONCURRENCY": "0"}); err == nil {
+		t.Errorf("expected error for zero concurrency")
+	}
+}
+
+func TestCronJob(t *testing.T) {
+	vs, c := env.CronJob("report")
+	batch := vs.Int("BATCH_SIZE", "rows per batch", env.Default("100"))
+	if err := vs.Parse(testGetter{"REPORT_CONCURRENCY": "2"}); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+	expected := &env.CronJobConfig{LogLevel: "info", ShutdownTimeout: 30 * time.Second, Concurrency: 2}
+	if !reflect.DeepEqual(c, expected) || *batch != 100 {
+		t.Errorf("got %+v and batch size %d, expected %+v and 100", c, *batch, expected)
+	}
+}
@@ -0,0 +1,149 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ANSI escape sequences used for terminal output.
+const (
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiReset  = "\x1b[0m"
+)
+
+// prettyOutput reports whether output to f should be laid out for a terminal: f is
+// a terminal, or CLICOLOR_FORCE requests terminal output anyway.
+func prettyOutput(f *os.File) bool {
+	if v := os.Getenv("CLICOLOR_FORCE"); v != "" && v != "0" {
+		return true
+	}
+	return isTerminal(f)
+}
+
+// terminalWidth returns the width of the terminal from COLUMNS, or 80.
+func terminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return 80
+}
+
+// painter applies ANSI colors to text if enabled.
+type painter bool
+
+func (p painter) paint(code, s string) string {
+	if !p || s == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// wrap splits s into lines of at most width characters, breaking at spaces.  Words
+// longer than width are not broken.
+func wrap(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// varStatus returns the status of x after parsing failed with errs.
+func varStatus(x *Var, errs []error) string {
+	for _, err := range errs {
+		var missing *MissingError
+		if errors.As(err, &missing) && missing.Name == x.Name {
+			return "missing"
+		}
+		var invalid *ParseError
+		if errors.As(err, &invalid) && invalid.Name == x.Name {
+			return "invalid"
+		}
+	}
+	switch {
+	case x.source != "":
+		return "set"
+	case x.def != nil:
+		return "default"
+	}
+	return "unset"
+}
+
+// writeUsagePretty writes the errors in err followed by the status and usage of each
+// variable in the set to w, with aligned columns and usage wrapped to width, using
+// color if enabled.
+func (v *VarSet) writeUsagePretty(w io.Writer, err error, color bool, width int) {
+	p := painter(color)
+
+	var errs []error
+	if err != nil {
+		errs = []error{err}
+		if es, ok := err.(Errors); ok {
+			errs = es
+		}
+		fmt.Fprintf(w, "%v\n", p.paint(ansiBold, "Errors:"))
+		for _, e := range errs {
+			fmt.Fprintf(w, "  %v %v\n", p.paint(ansiRed, "✗"), e)
+		}
+		fmt.Fprintln(w)
+	}
+
+	nameWidth := 0
+	v.Visit(func(x *Var) {
+		if len(x.Name) > nameWidth {
+			nameWidth = len(x.Name)
+		}
+	})
+	const statusWidth = len("missing")
+	indent := 2 + nameWidth + 2 + statusWidth + 2
+
+	fmt.Fprintf(w, "%v\n", p.paint(ansiBold, "Environment variables:"))
+	v.Visit(func(x *Var) {
+		status := varStatus(x, errs)
+		code := ansiGreen
+		switch status {
+		case "missing", "invalid":
+			code = ansiRed
+		case "default", "unset":
+			code = ansiYellow
+		}
+
+		desc := x.Usage
+		switch constraint, example := Describe(x.Value); {
+		case constraint != "" && example != "":
+			desc += fmt.Sprintf(" (%v, i.e. %q)", constraint, example)
+		case constraint != "":
+			desc += fmt.Sprintf(" (%v)", constraint)
+		case example != "":
+			desc += fmt.Sprintf(" (i.e. %q)", example)
+		}
+
+		lines := wrap(desc, width-indent)
+		fmt.Fprintf(w, "  %v%v  %v%v  %v\n",
+			p.paint(ansiBold, x.Name), strings.Repeat(" ", nameWidth-len(x.Name)),
+			p.paint(code, status), strings.Repeat(" ", statusWidth-len(status)),
+			lines[0])
+		for _, line := range lines[1:] {
+			fmt.Fprintf(w, "%v%v\n", strings.Repeat(" ", indent), line)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package env
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is a rate given as a count of events per time interval,
+// i.e. 100 per second.
+type Limit struct {
+	N   int           // number of events
+	Per time.Duration // interval
+}
+
+// PerSecond returns the rate as events per second, which can be converted
+// directly to a rate.Limit.
+func (r Limit) PerSecond() float64 {
+	if r.Per == 0 {
+		return 0
+	}
+	return float64(r.N) / r.Per.Seconds()
+}
+
+// String implements fmt.Stringer.
+func (r Limit) String() string {
+	per := r.Per.String()
+	switch r.Per {
+	case time.Second:
+		per = "s"
+	case time.Minute:
+		per = "m"
+	case time.Hour:
+		per = "h"
+	}
+	return strconv.Itoa(r.N) + "/" + per
+}
+
+// parseRate parses rates of the form "N/interval", where interval is either a
+// unit (s, m, h...) or a duration (30s, 100ms).
+func parseRate(x string) (Limit, error) {
+	i := strings.Index(x, "/")
+	if i < 0 {
+		return Limit{}, errors.New("parsing " + strconv.Quote(x) + ": expected count/interval")
+	}
+
+	n, err := strconv.Atoi(x[:i])
+	if err != nil || n < 0 {
+		return Limit{}, errors.New("parsing " + strconv.Quote(x) + ": invalid count")
+	}
+
+	per := x[i+1:]
+	if per != "" && !('0' <= per[0] && per[0] <= '9' || per[0] == '.') {
+		per = "1" + per
+	}
+	d, err := time.ParseDuration(per)
+	if err != nil {
+		return Limit{}, errors.New("parsing " + strconv.Quote(x) + ": invalid interval")
+	}
+	if d <= 0 {
+		return Limit{}, errors.New("parsing " + strconv.Quote(x) + ": interval must be positive")
+	}
+	return Limit{N: n, Per: d}, nil
+}
+
+type rateValue Limit
+
+func newRateValue(x Limit, p *Limit) *rateValue {
+	*p = x
+	return (*rateValue)(p)
+}
+
+func (v *rateValue) Set(x string) error {
+	r, err := parseRate(x)
+	if err != nil {
+		return err
+	}
+	*v = rateValue(r)
+	return nil
+}
+
+func (v *rateValue) String() string {
+	return Limit(*v).String()
+}
+
+func (v *rateValue) typeName() string { return "rate" }
+
+func (v *rateValue) Constraint() string { return "rate (count/interval)" }
+func (v *rateValue) Example() string    { return "100/s" }
+
+// Rate defines a Limit variable with specified name and usage string.  Values are
+// given as a count per interval, where the interval is a unit or duration, i.e.
+// "100/s", "5000/m" or "10/100ms".
+// The return value is the address of a Limit variable that stores the value of the variable.
+func (v *VarSet) Rate(name, usage string, opts ...Option) *Limit {
+	p := new(Limit)
+	v.Var(newRateValue(Limit{}, p), name, usage, opts...)
+	return p
+}
+
+// Rate defines a Limit variable with specified name and usage string.  Values are
+// given as a count per interval, i.e. "100/s".
+// The return value is the address of a Limit variable that stores the value of the variable.
+func Rate(name, usage string, opts ...Option) *Limit {
+	return CmdVar.Rate(name, usage, opts...)
+}
@@ -0,0 +1,52 @@
+package env_test
+
+import (
+	"testing"
+	"time"
+
+	"code.sajari.com/env"
+)
+
+func TestRate(t *testing.T) {
+	tests := []struct {
+		in        string
+		out       env.Limit
+		perSecond float64
+		wantErr   bool
+	}{
+		// Valid
+		{"100/s", env.Limit{N: 100, Per: time.Second}, 100, false},
+		{"5000/m", env.Limit{N: 5000, Per: time.Minute}, 5000.0 / 60, false},
+		{"10/100ms", env.Limit{N: 10, Per: 100 * time.Millisecond}, 100, false},
+		{"1/h", env.Limit{N: 1, Per: time.Hour}, 1.0 / 3600, false},
+		{"0/s", env.Limit{N: 0, Per: time.Second}, 0, false},
+
+		// Invalid
+		{"", env.Limit{}, 0, true},
+		{"100", env.Limit{}, 0, true},
+		{"/s", env.Limit{}, 0, true},
+		{"a/s", env.Limit{}, 0, true},
+		{"-1/s", env.Limit{}, 0, true},
+		{"100/", env.Limit{}, 0, true},
+		{"100/x", env.Limit{}, 0, true},
+		{"100/0s", env.Limit{}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			r := vs.Rate("RATE", "rate test")
+
+			if err := vs.Parse(testGetter{"RATE": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if *r != tt.out {
+				t.Errorf(" = %v, expected %v", *r, tt.out)
+			}
+			if ps := r.PerSecond(); ps != tt.perSecond {
+				t.Errorf("PerSecond() = %v, expected %v", ps, tt.perSecond)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Schema describes the variables of a VarSet at a version of an application, for
+// recording alongside each release and comparing with DiffSchemas.  It is encoded as
+// JSON by encoding/json, and read back by ReadSchema.
+type Schema struct {
+	Version string      `json:"version"`
+	Vars    []SchemaVar `json:"vars"` // sorted by name
+}
+
+// SchemaVar describes a variable in a Schema.
+type SchemaVar struct {
+	Name       string   `json:"name"`
+	Usage      string   `json:"usage,omitempty"`
+	Type       string   `json:"type"`
+	Constraint string   `json:"constraint,omitempty"`
+	Scope      string   `json:"scope"`
+	Default    string   `json:"default,omitempty"` // empty for secrets
+	Aliases    []string `json:"aliases,omitempty"` // fallback names, in lookup order
+	Optional   bool     `json:"optional,omitempty"`
+	Secret     bool     `json:"secret,omitempty"`
+}
+
+// SchemaVersioned returns the schema of the variables in the set, stamped with the
+// application version.
+func (v *VarSet) SchemaVersioned(appVersion string) *Schema {
+	s := &Schema{Version: appVersion, Vars: []SchemaVar{}}
+	v.Visit(func(x *Var) {
+		constraint, _ := Describe(x.Value)
+		sv := SchemaVar{
+			Name:       x.Name,
+			Usage:      x.Usage,
+			Type:       valueType(x.Value),
+			Constraint: constraint,
+			Scope:      x.Scope.String(),
+			Optional:   x.optional,
+			Secret:     x.secret,
+		}
+		if len(x.fallbacks) > 0 {
+			sv.Aliases = append([]string(nil), x.fallbacks...)
+		}
+		if x.def != nil && !x.secret {
+			sv.Default = x.def()
+		}
+		s.Vars = append(s.Vars, sv)
+	})
+	sort.Slice(s.Vars, func(i, j int) bool { return s.Vars[i].Name < s.Vars[j].Name })
+	return s
+}
+
+// ReadSchema reads a Schema encoded as JSON from r.
+func ReadSchema(r io.Reader) (*Schema, error) {
+	s := new(Schema)
+	if err := json.NewDecoder(r).Decode(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SchemaDiff is the changelog between two schemas, as returned by DiffSchemas.  Each
+// list is sorted by name.
+type SchemaDiff struct {
+	From, To string // schema versions
+
+	Added   []SchemaVar
+	Removed []SchemaVar
+	Changed []SchemaChange
+}
+
+// SchemaChange describes a variable which is in both schemas with different
+// descriptions.
+type SchemaChange struct {
+	Name   string
+	Fields []string // names of the changed fields, i.e. "type" or "default"
+	Old    SchemaVar
+	New    SchemaVar
+}
+
+// Empty reports whether there are no differences between the schemas.
+func (d *SchemaDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String returns the changelog as text suitable for release notes:
+//
+//	Configuration changes from 1.0.0 to 1.1.0:
+//	+ CACHE_SIZE: cache size in entries
+//	- LEGACY_MODE
+//	~ PORT: default
+func (d *SchemaDiff) String() string {
+	var b strings.Builder
+	if d.Empty() {
+		fmt.Fprintf(&b, "No configuration changes from %v to %v.\n", d.From, d.To)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Configuration changes from %v to %v:\n", d.From, d.To)
+	for _, x := range d.Added {
+		if x.Usage == "" {
+			fmt.Fprintf(&b, "+ %v\n", x.Name)
+			continue
+		}
+		fmt.Fprintf(&b, "+ %v: %v\n", x.Name, x.Usage)
+	}
+	for _, x := range d.Removed {
+		fmt.Fprintf(&b, "- %v\n", x.Name)
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(&b, "~ %v: %v\n", c.Name, strings.Join(c.Fields, ", "))
+	}
+	return b.String()
+}
+
+// DiffSchemas returns the changes to variables from the old schema to the new one.
+func DiffSchemas(old, new *Schema) *SchemaDiff {
+	d := &SchemaDiff{From: old.Version, To: new.Version}
+
+	olds := make(map[string]SchemaVar, len(old.Vars))
+	for _, x := range old.Vars {
+		olds[x.Name] = x
+	}
+	news := make(map[string]bool, len(new.Vars))
+	for _, x := range new.Vars {
+		news[x.Name] = true
+		o, ok := olds[x.Name]
+		if !ok {
+			d.Added = append(d.Added, x)
+			continue
+		}
+		if fields := changedFields(o, x); len(fields) > 0 {
+			d.Changed = append(d.Changed, SchemaChange{Name: x.Name, Fields: fields, Old: o, New: x})
+		}
+	}
+	for _, x := range old.Vars {
+		if !news[x.Name] {
+			d.Removed = append(d.Removed, x)
+		}
+	}
+
+	sort.Slice(d.Added, func(i, j int) bool { return d.Added[i].Name < d.Added[j].Name })
+	sort.Slice(d.Removed, func(i, j int) bool { return d.Removed[i].Name < d.Removed[j].Name })
+	sort.Slice(d.Changed, func(i, j int) bool { return d.Changed[i].Name < d.Changed[j].Name })
+	return d
+}
+
+// changedFields returns the JSON names of the fields which differ between a and b.
+func changedFields(a, b SchemaVar) []string {
+	var fields []string
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	t := va.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
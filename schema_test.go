@@ -0,0 +1,68 @@
+package env_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestSchemaVersioned(t *testing.T) {
+	vs := env.NewVarSet("")
+	vs.Int("PORT", "listen port", env.Default("8080"))
+	vs.String("TOKEN", "api token", env.Secret(), env.Default("dev"))
+	vs.Bool("DEBUG", "debug logging", env.Optional(), env.FallbackTo("VERBOSE"))
+
+	s := vs.SchemaVersioned("1.0.0")
+	expected := &env.Schema{
+		Version: "1.0.0",
+		Vars: []env.SchemaVar{
+			{Name: "DEBUG", Usage: "debug logging", Type: "bool", Constraint: "boolean", Scope: "static", Aliases: []string{"VERBOSE"}, Optional: true},
+			{Name: "PORT", Usage: "listen port", Type: "int", Constraint: "integer", Scope: "static", Default: "8080"},
+			{Name: "TOKEN", Usage: "api token", Type: "string", Scope: "static", Secret: true},
+		},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("got %+v, expected %+v", s, expected)
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(s); err != nil {
+		t.Fatal(err)
+	}
+	got, err := env.ReadSchema(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error from ReadSchema: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ReadSchema() = %+v, expected %+v", got, expected)
+	}
+}
+
+func TestDiffSchemas(t *testing.T) {
+	v1 := env.NewVarSet("")
+	v1.Int("PORT", "listen port", env.Default("8080"))
+	v1.Bool("LEGACY_MODE", "legacy mode")
+	v1.String("NAME", "service name")
+
+	v2 := env.NewVarSet("")
+	v2.String("PORT", "listen port", env.Default(":8080"))
+	v2.Int("CACHE_SIZE", "cache size in entries")
+	v2.String("NAME", "service name")
+
+	d := env.DiffSchemas(v1.SchemaVersioned("1.0.0"), v2.SchemaVersioned("1.1.0"))
+	expected := "Configuration changes from 1.0.0 to 1.1.0:\n" +
+		"+ CACHE_SIZE: cache size in entries\n" +
+		"- LEGACY_MODE\n" +
+		"~ PORT: type, constraint, default\n"
+	if got := d.String(); got != expected {
+		t.Errorf("got:\n%v\nexpected:\n%v", got, expected)
+	}
+
+	d = env.DiffSchemas(v2.SchemaVersioned("1.1.0"), v2.SchemaVersioned("1.1.1"))
+	if !d.Empty() {
+		t.Errorf("expected no changes, got %+v", d)
+	}
+}
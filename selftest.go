@@ -0,0 +1,81 @@
+package env
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// selfTestCase is a validator and examples of values it must accept and reject.
+type selfTestCase struct {
+	name    string
+	define  func(v *VarSet)
+	valid   []string
+	invalid []string
+}
+
+// selfTestCorpus is the corpus of values checked by SelfTest.  Values which depend
+// on the platform (time zones, file paths, host name resolution) are included
+// deliberately.
+var selfTestCorpus = []selfTestCase{
+	{"Int", func(v *VarSet) { v.Int("V", "") }, []string{"0", "-1", "42"}, []string{"", "1.5", "0x", "one"}},
+	{"Bool", func(v *VarSet) { v.Bool("V", "") }, []string{"true", "false", "1", "0", "T", "F"}, []string{"", "yes", "on"}},
+	{"Duration", func(v *VarSet) { v.Duration("V", "") }, []string{"0", "1s", "1h30m", "-5ms", "1.5us"}, []string{"", "1", "1d", "s"}},
+	{"BindAddr", func(v *VarSet) { v.BindAddr("V", "") }, []string{":8080", "localhost:80", "127.0.0.1:0", "[::1]:443"}, []string{"", "8080", "localhost", "localhost:"}},
+	{"DialAddr", func(v *VarSet) { v.DialAddr("V", "") }, []string{"localhost:80", "127.0.0.1:5432", "[::1]:443"}, []string{"", ":80", "localhost"}},
+	{"Hostname", func(v *VarSet) { v.Hostname("V", "") }, []string{"localhost", "example.com", "a-b.example.com"}, []string{"", "-a.com", "a..com", "a_b.com"}},
+	{"LanguageTag", func(v *VarSet) { v.LanguageTag("V", "") }, []string{"en", "en-US", "zh-Hant-TW"}, []string{"", "e", "en_US", "en-"}},
+	{"Location", func(v *VarSet) { v.Location("V", "") }, []string{"UTC", "Local", "America/New_York", "Europe/London"}, []string{"Nowhere/City"}},
+	{"FileMode", func(v *VarSet) { v.FileMode("V", "") }, []string{"0644", "755", "0"}, []string{"", "0999", "rw-r--r--"}},
+	{"MAC", func(v *VarSet) { v.MAC("V", "") }, []string{"00:00:5e:00:53:01", "00-00-5E-00-53-01"}, []string{"", "00:00:5e", "zz:00:5e:00:53:01"}},
+	{"Percent", func(v *VarSet) { v.Percent("V", "") }, []string{"0", "25%", "100"}, []string{"", "-1", "101%", "x%"}},
+	{"BigInt", func(v *VarSet) { v.BigInt("V", "", 10) }, []string{"0", "-1", "123456789012345678901234567890"}, []string{"", "1.5", "0x10"}},
+	{"Dir", func(v *VarSet) { v.Dir("V", "") }, []string{os.TempDir()}, []string{"", os.Args[0]}},
+	{"Rate", func(v *VarSet) { v.Rate("V", "") }, []string{"100/s", "5/m", "10/30s"}, []string{"", "100", "x/s", "1/x"}},
+	{"Semver", func(v *VarSet) { v.Semver("V", "") }, []string{"1.2.3", "0.0.1-alpha.1", "1.0.0+build"}, []string{"", "1.2", "01.2.3", "1.2.3-"}},
+	{"Cron", func(v *VarSet) { v.Cron("V", "") }, []string{"* * * * *", "0 9 * * 1-5", "*/15 * * * *"}, []string{"", "* * * *", "60 * * * *"}},
+	{"CORSOrigins", func(v *VarSet) { v.CORSOrigins("V", "") }, []string{"*", "https://example.com,http://localhost:3000"}, []string{"", "example.com"}},
+	{"WebhookURL", func(v *VarSet) { v.WebhookURL("V", "", false) }, []string{"https://93.184.216.34/hook"}, []string{"", "ftp://93.184.216.34/hook", "https://127.0.0.1/hook"}},
+}
+
+// SelfTest checks the package's validators against a built-in corpus of valid and
+// invalid values, writing a report to w.  It is intended for checking behaviour on
+// unusual platforms before shipping, and returns an error if any check failed.
+func SelfTest(w io.Writer) error {
+	failed := 0
+	for _, tc := range selfTestCorpus {
+		var problems []string
+		check := func(x string, valid bool) {
+			v := NewVarSet("")
+			tc.define(v)
+			err := v.Parse(MapGetter{"V": x})
+			switch {
+			case valid && err != nil:
+				problems = append(problems, fmt.Sprintf("rejected valid %q: %v", x, err))
+			case !valid && err == nil:
+				problems = append(problems, fmt.Sprintf("accepted invalid %q", x))
+			}
+		}
+		for _, x := range tc.valid {
+			check(x, true)
+		}
+		for _, x := range tc.invalid {
+			check(x, false)
+		}
+
+		if len(problems) == 0 {
+			fmt.Fprintf(w, "ok   %v\n", tc.name)
+			continue
+		}
+		failed++
+		fmt.Fprintf(w, "FAIL %v\n", tc.name)
+		for _, p := range problems {
+			fmt.Fprintf(w, "     %v\n", p)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("env self test: %d of %d validators failed", failed, len(selfTestCorpus))
+	}
+	return nil
+}
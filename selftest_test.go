@@ -0,0 +1,19 @@
+package env_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestSelfTest(t *testing.T) {
+	var buf bytes.Buffer
+	if err := env.SelfTest(&buf); err != nil {
+		t.Errorf("env.SelfTest() = %v, expected nil:\n%v", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "ok   BindAddr\n") {
+		t.Errorf("report does not include BindAddr:\n%v", buf.String())
+	}
+}
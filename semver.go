@@ -0,0 +1,204 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version (see https://semver.org).
+type Version struct {
+	Major, Minor, Patch int
+	Pre                 []string // pre-release identifiers
+	Build               []string // build metadata identifiers
+}
+
+// ParseVersion parses a semantic version of the form MAJOR.MINOR.PATCH, with optional
+// pre-release and build metadata suffixes (i.e. "1.2.3-rc.1+build.5").  A leading "v"
+// is permitted.
+func ParseVersion(x string) (Version, error) {
+	s := strings.TrimPrefix(x, "v")
+
+	var v Version
+	if i := strings.Index(s, "+"); i >= 0 {
+		build, err := parseIdentifiers(s[i+1:], false)
+		if err != nil {
+			return Version{}, fmt.Errorf("parsing %q: build metadata: %v", x, err)
+		}
+		v.Build = build
+		s = s[:i]
+	}
+	if i := strings.Index(s, "-"); i >= 0 {
+		pre, err := parseIdentifiers(s[i+1:], true)
+		if err != nil {
+			return Version{}, fmt.Errorf("parsing %q: pre-release: %v", x, err)
+		}
+		v.Pre = pre
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("parsing %q: expected MAJOR.MINOR.PATCH", x)
+	}
+	for i, p := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		n, err := parseNumericIdentifier(parts[i])
+		if err != nil {
+			return Version{}, fmt.Errorf("parsing %q: %v", x, err)
+		}
+		*p = n
+	}
+	return v, nil
+}
+
+func parseNumericIdentifier(x string) (int, error) {
+	if x == "" || !isDigits(x) {
+		return 0, fmt.Errorf("invalid number %q", x)
+	}
+	if len(x) > 1 && x[0] == '0' {
+		return 0, fmt.Errorf("number %q has leading zero", x)
+	}
+	return strconv.Atoi(x)
+}
+
+func parseIdentifiers(x string, pre bool) ([]string, error) {
+	ids := strings.Split(x, ".")
+	for _, id := range ids {
+		if id == "" {
+			return nil, errors.New("empty identifier")
+		}
+		for _, r := range id {
+			if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '-') {
+				return nil, fmt.Errorf("invalid character %q in identifier %q", r, id)
+			}
+		}
+		if pre && len(id) > 1 && id[0] == '0' && isDigits(id) {
+			return nil, fmt.Errorf("identifier %q has leading zero", id)
+		}
+	}
+	return ids, nil
+}
+
+// String implements fmt.Stringer.
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Pre) > 0 {
+		s += "-" + strings.Join(v.Pre, ".")
+	}
+	if len(v.Build) > 0 {
+		s += "+" + strings.Join(v.Build, ".")
+	}
+	return s
+}
+
+// Compare returns -1, 0 or +1 depending on whether v has lower, equal or higher
+// precedence than w.  Build metadata is ignored.
+func (v Version) Compare(w Version) int {
+	if c := compareInt(v.Major, w.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, w.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, w.Patch); c != 0 {
+		return c
+	}
+
+	// A version without pre-release identifiers has higher precedence.
+	switch {
+	case len(v.Pre) == 0 && len(w.Pre) == 0:
+		return 0
+	case len(v.Pre) == 0:
+		return 1
+	case len(w.Pre) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.Pre) && i < len(w.Pre); i++ {
+		if c := comparePre(v.Pre[i], w.Pre[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(v.Pre), len(w.Pre))
+}
+
+// LessThan reports whether v has lower precedence than w.
+func (v Version) LessThan(w Version) bool {
+	return v.Compare(w) < 0
+}
+
+// AtLeast reports whether v has equal or higher precedence than w.
+func (v Version) AtLeast(w Version) bool {
+	return v.Compare(w) >= 0
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// comparePre compares pre-release identifiers: numeric identifiers are
+// compared numerically and have lower precedence than alphanumeric ones,
+// which are compared lexically.
+func comparePre(a, b string) int {
+	an, bn := isDigits(a), isDigits(b)
+	switch {
+	case an && bn:
+		if c := compareInt(len(a), len(b)); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	case an:
+		return -1
+	case bn:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+type versionValue Version
+
+func newVersionValue(x Version, p *Version) *versionValue {
+	*p = x
+	return (*versionValue)(p)
+}
+
+func (v *versionValue) Set(x string) error {
+	ver, err := ParseVersion(x)
+	if err != nil {
+		return err
+	}
+	*v = versionValue(ver)
+	return nil
+}
+
+func (v *versionValue) String() string {
+	return Version(*v).String()
+}
+
+func (v *versionValue) typeName() string { return "version" }
+
+func (v *versionValue) Constraint() string { return "semantic version" }
+func (v *versionValue) Example() string    { return "1.2.3" }
+
+// Semver defines a Version variable with specified name and usage string, validated
+// as a semantic version (i.e. "1.2.3" or "v2.0.0-rc.1").
+// The return value is the address of a Version variable that stores the value of the variable.
+func (v *VarSet) Semver(name, usage string, opts ...Option) *Version {
+	p := new(Version)
+	v.Var(newVersionValue(Version{}, p), name, usage, opts...)
+	return p
+}
+
+// Semver defines a Version variable with specified name and usage string, validated
+// as a semantic version.
+// The return value is the address of a Version variable that stores the value of the variable.
+func Semver(name, usage string, opts ...Option) *Version {
+	return CmdVar.Semver(name, usage, opts...)
+}
@@ -0,0 +1,104 @@
+package env_test
+
+import (
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestSemver(t *testing.T) {
+	tests := []struct {
+		in      string
+		out     string
+		wantErr bool
+	}{
+		// Valid
+		{"1.2.3", "1.2.3", false},
+		{"v1.2.3", "1.2.3", false},
+		{"0.0.0", "0.0.0", false},
+		{"1.0.0-rc.1", "1.0.0-rc.1", false},
+		{"1.0.0-alpha-1+build.5", "1.0.0-alpha-1+build.5", false},
+		{"1.0.0+001", "1.0.0+001", false},
+
+		// Invalid
+		{"", "0.0.0", true},
+		{"1", "0.0.0", true},
+		{"1.2", "0.0.0", true},
+		{"1.2.3.4", "0.0.0", true},
+		{"01.2.3", "0.0.0", true},
+		{"1.2.a", "0.0.0", true},
+		{"1.2.3-", "0.0.0", true},
+		{"1.2.3-01", "0.0.0", true},
+		{"1.2.3-a..b", "0.0.0", true},
+		{"1.2.3+", "0.0.0", true},
+		{"1.2.3-a_b", "0.0.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			v := vs.Semver("VERSION", "semver test")
+
+			if err := vs.Parse(testGetter{"VERSION": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if s := v.String(); s != tt.out {
+				t.Errorf(" = %v, expected %v", s, tt.out)
+			}
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	// In order of increasing precedence.
+	versions := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+
+	for i := range versions {
+		for j := range versions {
+			a, err := env.ParseVersion(versions[i])
+			if err != nil {
+				t.Fatalf("env.ParseVersion(%q) = %v", versions[i], err)
+			}
+			b, err := env.ParseVersion(versions[j])
+			if err != nil {
+				t.Fatalf("env.ParseVersion(%q) = %v", versions[j], err)
+			}
+
+			want := 0
+			switch {
+			case i < j:
+				want = -1
+			case i > j:
+				want = 1
+			}
+			if c := a.Compare(b); c != want {
+				t.Errorf("%v.Compare(%v) = %d, expected %d", a, b, c, want)
+			}
+			if lt := a.LessThan(b); lt != (want < 0) {
+				t.Errorf("%v.LessThan(%v) = %v, expected %v", a, b, lt, want < 0)
+			}
+			if al := a.AtLeast(b); al != (want >= 0) {
+				t.Errorf("%v.AtLeast(%v) = %v, expected %v", a, b, al, want >= 0)
+			}
+		}
+	}
+
+	a, _ := env.ParseVersion("1.0.0+build.1")
+	b, _ := env.ParseVersion("1.0.0+build.2")
+	if c := a.Compare(b); c != 0 {
+		t.Errorf("%v.Compare(%v) = %d, expected build metadata to be ignored", a, b, c)
+	}
+}
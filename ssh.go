@@ -0,0 +1,172 @@
+package env
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+type sshKeyValue struct {
+	p    *[]byte
+	path string // path to key file, empty if given inline
+}
+
+func (v *sshKeyValue) Set(x string) error {
+	data, path := []byte(x), ""
+	if !strings.HasPrefix(strings.TrimSpace(x), "-----BEGIN") {
+		b, err := ioutil.ReadFile(x)
+		if err != nil {
+			return err
+		}
+		data, path = b, x
+	}
+	if err := checkSSHPrivateKey(data); err != nil {
+		return err
+	}
+	*v.p = data
+	v.path = path
+	return nil
+}
+
+// String returns the path to the key file.  Inline keys are not returned, so that
+// they are not exposed when variables are dumped.
+func (v *sshKeyValue) String() string {
+	if v.path == "" && len(*v.p) > 0 {
+		return "(inline key)"
+	}
+	return v.path
+}
+
+func (v *sshKeyValue) typeName() string { return "key" }
+
+func (v *sshKeyValue) Constraint() string { return "PEM private key (path or inline)" }
+func (v *sshKeyValue) Example() string    { return "/path/to/id_ed25519" }
+
+// checkSSHPrivateKey checks that data contains a single PEM encoded private key in
+// either OpenSSH, PKCS #1, SEC 1 or PKCS #8 format.
+func checkSSHPrivateKey(data []byte) error {
+	b, rest := pem.Decode(data)
+	if b == nil {
+		return errors.New("no PEM private key found")
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return errors.New("unexpected data after PEM private key")
+	}
+	if _, ok := b.Headers["Proc-Type"]; ok {
+		return errors.New("encrypted PEM private keys are not supported")
+	}
+
+	var err error
+	switch b.Type {
+	case "OPENSSH PRIVATE KEY":
+		if !bytes.HasPrefix(b.Bytes, []byte("openssh-key-v1\x00")) {
+			err = errors.New("invalid OpenSSH private key")
+		}
+	case "RSA PRIVATE KEY":
+		_, err = x509.ParsePKCS1PrivateKey(b.Bytes)
+	case "EC PRIVATE KEY":
+		_, err = x509.ParseECPrivateKey(b.Bytes)
+	case "PRIVATE KEY":
+		_, err = x509.ParsePKCS8PrivateKey(b.Bytes)
+	default:
+		err = fmt.Errorf("unsupported PEM block type %q", b.Type)
+	}
+	return err
+}
+
+// isKnownHosts checks that x is the path to a file in OpenSSH known_hosts format.
+func isKnownHosts(x string) error {
+	f, err := os.Open(x)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for n := 1; s.Scan(); n++ {
+		if err := checkKnownHostsLine(s.Text()); err != nil {
+			return fmt.Errorf("line %d: %v", n, err)
+		}
+	}
+	return s.Err()
+}
+
+// checkKnownHostsLine checks a line of the form:
+//
+//	[@marker] hosts keytype base64-key [comment]
+func checkKnownHostsLine(line string) error {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return nil
+	}
+	if strings.HasPrefix(fields[0], "@") {
+		if fields[0] != "@cert-authority" && fields[0] != "@revoked" {
+			return fmt.Errorf("unknown marker %q", fields[0])
+		}
+		fields = fields[1:]
+	}
+	if len(fields) < 3 {
+		return errors.New("expected hosts, key type and key")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(fields[2])
+	if err != nil {
+		return errors.New("invalid base64 key")
+	}
+	// The key is prefixed by its type as a length-prefixed string.
+	if len(key) < 4 {
+		return errors.New("invalid key")
+	}
+	n := binary.BigEndian.Uint32(key)
+	if uint64(len(key)-4) < uint64(n) || string(key[4:4+n]) != fields[1] {
+		return fmt.Errorf("key does not match key type %q", fields[1])
+	}
+	return nil
+}
+
+// SSHPrivateKey defines a variable with specified name and usage string holding a PEM encoded
+// private key, given either inline or as a path to a key file.  Keys in OpenSSH, PKCS #1, SEC 1
+// and PKCS #8 formats are accepted, suitable for passing to ssh.ParsePrivateKey.
+// Encrypted PEM keys are not supported.
+// The return value is the address of a byte slice which stores the PEM encoded key.
+func (v *VarSet) SSHPrivateKey(name, usage string, opts ...Option) *[]byte {
+	p := new([]byte)
+	v.Var(&sshKeyValue{p: p}, name, usage, opts...)
+	return p
+}
+
+// SSHKnownHosts defines a string variable with specified name and usage string validated as
+// a path to a file in OpenSSH known_hosts format.
+// The return value is the address of a string variable that stores the value of the variable.
+func (v *VarSet) SSHKnownHosts(name, usage string, opts ...Option) *string {
+	p := new(string)
+	v.Var(checkedValue{
+		fn:         isKnownHosts,
+		constraint: "known_hosts file",
+		example:    "/path/to/known_hosts",
+		Value:      newStringValue("", p),
+	}, name, usage, opts...)
+	return p
+}
+
+// SSHPrivateKey defines a variable with specified name and usage string holding a PEM encoded
+// private key, given either inline or as a path to a key file.
+// The return value is the address of a byte slice which stores the PEM encoded key.
+func SSHPrivateKey(name, usage string, opts ...Option) *[]byte {
+	return CmdVar.SSHPrivateKey(name, usage, opts...)
+}
+
+// SSHKnownHosts defines a string variable with specified name and usage string validated as
+// a path to a file in OpenSSH known_hosts format.
+// The return value is the address of a string variable that stores the value of the variable.
+func SSHKnownHosts(name, usage string, opts ...Option) *string {
+	return CmdVar.SSHKnownHosts(name, usage, opts...)
+}
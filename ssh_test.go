@@ -0,0 +1,109 @@
+package env_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestSSHPrivateKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("could not marshal key: %v", err)
+	}
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	opensshPEM := string(pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: []byte("openssh-key-v1\x00rest")}))
+
+	tmpFile, err := ioutil.TempFile("", "SSHPrivateKey")
+	if err != nil {
+		t.Fatalf("could not create temporary file: %v", err)
+	}
+	defer tmpFile.Close()
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.WriteString(keyPEM); err != nil {
+		t.Fatalf("could not write temporary file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		in      string
+		wantErr bool
+	}{
+		{"inline", keyPEM, false},
+		{"path", tmpFile.Name(), false},
+		{"openssh", opensshPEM, false},
+
+		{"missing file", "filedoesnotexist", true},
+		{"not pem", "-----BEGIN nothing", true},
+		{"bad openssh", string(pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: []byte("x")})), true},
+		{"bad ec", string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("x")})), true},
+		{"certificate", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), true},
+		{"two keys", keyPEM + keyPEM, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			p := vs.SSHPrivateKey("KEY", "ssh key test")
+
+			if err := vs.Parse(testGetter{"KEY": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(*p) == 0 {
+				t.Errorf("expected key data to be set")
+			}
+		})
+	}
+}
+
+func TestSSHKnownHosts(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte("\x00\x00\x00\x0bssh-ed25519\x00\x00\x00\x20abcdefghijklmnopqrstuvwxyz012345"))
+
+	tests := []struct {
+		name    string
+		in      string
+		wantErr bool
+	}{
+		{"valid", "# comment\n\ngithub.com,192.30.255.112 ssh-ed25519 " + key + " comment\n", false},
+		{"marker", "@cert-authority *.example.com ssh-ed25519 " + key + "\n", false},
+		{"hashed", "|1|c2FsdA==|aGFzaA== ssh-ed25519 " + key + "\n", false},
+
+		{"bad marker", "@trusted example.com ssh-ed25519 " + key + "\n", true},
+		{"missing key", "example.com ssh-ed25519\n", true},
+		{"bad base64", "example.com ssh-ed25519 !!!\n", true},
+		{"wrong type", "example.com ssh-rsa " + key + "\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile("", "SSHKnownHosts")
+			if err != nil {
+				t.Fatalf("could not create temporary file: %v", err)
+			}
+			defer tmpFile.Close()
+			defer os.Remove(tmpFile.Name())
+			if _, err := tmpFile.WriteString(tt.in); err != nil {
+				t.Fatalf("could not write temporary file: %v", err)
+			}
+
+			vs := env.NewVarSet("")
+			vs.SSHKnownHosts("KNOWN_HOSTS", "known hosts test")
+
+			if err := vs.Parse(testGetter{"KNOWN_HOSTS": tmpFile.Name()}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package env
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+// FromStdin allows the value of a secret variable to be read from stdin rather than
+// the environment, for use with tools which pipe secrets to the program instead of
+// exporting them.  If the variable is set to "-" then Parse reads its value from the
+// next line of stdin: where several variables are set to "-" they read successive lines
+// in the order they were defined.
+func FromStdin() Option {
+	return func(v *Var) {
+		v.stdin = true
+	}
+}
+
+// Stdin sets the reader used for FromStdin variables in place of os.Stdin.
+func Stdin(r io.Reader) ParseOption {
+	return func(c *parseConfig) {
+		c.stdin = &lineReader{r: r}
+	}
+}
+
+// lineReader reads lines from r, which is only read once it is first needed.
+type lineReader struct {
+	r  io.Reader
+	br *bufio.Reader
+}
+
+// readLine returns the next line, without its line ending.
+func (l *lineReader) readLine() (string, error) {
+	if l.br == nil {
+		l.br = bufio.NewReader(l.r)
+	}
+	line, err := l.br.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return "", errors.New("unexpected end of input")
+	}
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
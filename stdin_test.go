@@ -0,0 +1,48 @@
+package env_test
+
+import (
+	"strings"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestFromStdin(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     testGetter
+		stdin   string
+		token   string
+		key     string
+		wantErr bool
+	}{
+		{"env", testGetter{"TOKEN": "a", "KEY": "b"}, "ignored\n", "a", "b", false},
+		{"one", testGetter{"TOKEN": "-", "KEY": "b"}, "secret\n", "secret", "b", false},
+		{"two", testGetter{"TOKEN": "-", "KEY": "-"}, "secret\r\nkey", "secret", "key", false},
+		{"no line ending", testGetter{"TOKEN": "-", "KEY": "b"}, "secret", "secret", "b", false},
+
+		{"eof", testGetter{"TOKEN": "-", "KEY": "-"}, "secret\n", "secret", "", true},
+		{"empty", testGetter{"TOKEN": "-", "KEY": "b"}, "", "", "b", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			token := vs.String("TOKEN", "stdin test", env.FromStdin())
+			key := vs.String("KEY", "stdin test", env.FromStdin())
+
+			if err := vs.Parse(tt.env, env.Stdin(strings.NewReader(tt.stdin))); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if *token != tt.token || *key != tt.key {
+				t.Errorf(" = (%q, %q), expected (%q, %q)", *token, *key, tt.token, tt.key)
+			}
+		})
+	}
+
+	vs := env.NewVarSet("")
+	s := vs.String("NAME", "stdin test")
+	if err := vs.Parse(testGetter{"NAME": "-"}, env.Stdin(strings.NewReader("x\n"))); err != nil || *s != "-" {
+		t.Errorf("got (%q, %v), expected \"-\" for variable without FromStdin", *s, err)
+	}
+}
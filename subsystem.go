@@ -0,0 +1,121 @@
+package env
+
+import "strings"
+
+// Subsystem pairs the variables used by a library or component with hooks
+// which are run when the variables are parsed, re-parsed and when the host
+// application shuts down.
+//
+// Libraries can export a single *Subsystem which the host application
+// registers alongside its own, giving uniform parse, reload and
+// documentation behaviour across all embedded components.
+type Subsystem struct {
+	Vars *VarSet // variables used by the subsystem
+
+	Init     func() error // called after Vars are first parsed (optional)
+	Reload   func() error // called after Vars are re-parsed (optional)
+	Shutdown func() error // called when the host shuts down (optional)
+}
+
+// Subsystems is a list of subsystems managed by a host application.
+type Subsystems []*Subsystem
+
+func (s Subsystems) sets() []*VarSet {
+	sets := make([]*VarSet, 0, len(s))
+	for _, x := range s {
+		sets = append(sets, x.Vars)
+	}
+	return sets
+}
+
+// Init parses the variables of all subsystems from the environment provided
+// by the Getter (see ParseAll) and then calls each Init hook in order.
+// Init hooks are not called if parsing fails, and stop at the first hook
+// to return an error.
+func (s Subsystems) Init(g Getter) error {
+	if err := ParseAll(g, s.sets()...); err != nil {
+		return err
+	}
+	for _, x := range s {
+		if x.Init == nil {
+			continue
+		}
+		if err := x.Init(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestartRequiredError is returned from Subsystems.Reload when the values of
+// Static variables have changed in the environment.  Changes to Static variables
+// are not applied, so the process must be restarted to use them.
+type RestartRequiredError struct {
+	Names []string // names of the changed variables
+}
+
+// Error implements error.
+func (e *RestartRequiredError) Error() string {
+	return "restart required: " + strings.Join(e.Names, ", ") + " changed"
+}
+
+// Reload re-parses the variables of all subsystems from the environment
+// provided by the Getter and then calls each Reload hook in order.
+//
+// Static variables (see Scope) are not re-parsed and keep their current values.
+// If any have changed in the environment then, once all other variables
+// have been reloaded and hooks called, Reload returns a *RestartRequiredError
+// listing them.
+//
+// Parsing updates variables in place, so if parsing fails some variables may
+// hold new values.  Reload hooks are only called if parsing succeeds.
+func (s Subsystems) Reload(g Getter) error {
+	var changed []string
+	for _, x := range s {
+		changed = append(changed, x.Vars.changedStatic(g)...)
+	}
+
+	if err := parseAll(g, parseConfig{reload: true}, s.sets()); err != nil {
+		return err
+	}
+	for _, x := range s {
+		if x.Reload == nil {
+			continue
+		}
+		if err := x.Reload(); err != nil {
+			return err
+		}
+	}
+
+	if len(changed) > 0 {
+		return &RestartRequiredError{Names: changed}
+	}
+	return nil
+}
+
+// Shutdown calls each Shutdown hook in the reverse order to which the
+// subsystems are listed.  All hooks are called, and any errors are
+// returned as Errors.
+func (s Subsystems) Shutdown() error {
+	var errs []error
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i].Shutdown == nil {
+			continue
+		}
+		if err := s[i].Shutdown(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return Errors(errs)
+}
+
+// Visit visits the variables of each subsystem in turn, in the order in which
+// they were defined, calling fn for each.
+func (s Subsystems) Visit(fn func(*Var)) {
+	for _, x := range s {
+		x.Vars.Visit(fn)
+	}
+}
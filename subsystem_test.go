@@ -0,0 +1,84 @@
+package env_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestSubsystems(t *testing.T) {
+	var calls []string
+	hook := func(name string) func() error {
+		return func() error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	db := env.NewVarSet("db")
+	dbAddr := db.DialAddr("ADDR", "db address")
+
+	cache := env.NewVarSet("cache")
+	cacheSize := cache.Int("SIZE", "cache size", env.WithScope(env.Dynamic))
+
+	subs := env.Subsystems{
+		{Vars: db, Init: hook("db init"), Reload: hook("db reload"), Shutdown: hook("db shutdown")},
+		{Vars: cache, Init: hook("cache init"), Shutdown: hook("cache shutdown")},
+	}
+
+	if err := subs.Init(testGetter{"DB_ADDR": "localhost:1234", "CACHE_SIZE": "10"}); err != nil {
+		t.Fatalf("subs.Init() = %v, expected nil error", err)
+	}
+	if *dbAddr != "localhost:1234" || *cacheSize != 10 {
+		t.Errorf("unexpected values after Init: %q, %d", *dbAddr, *cacheSize)
+	}
+
+	if err := subs.Reload(testGetter{"DB_ADDR": "localhost:1234", "CACHE_SIZE": "15"}); err != nil {
+		t.Fatalf("subs.Reload() = %v, expected nil error", err)
+	}
+
+	err := subs.Reload(testGetter{"DB_ADDR": "localhost:5678", "CACHE_SIZE": "20"})
+	if re, ok := err.(*env.RestartRequiredError); !ok || !reflect.DeepEqual(re.Names, []string{"DB_ADDR"}) {
+		t.Fatalf("subs.Reload() = %v, expected restart required for DB_ADDR", err)
+	}
+	if *cacheSize != 20 {
+		t.Errorf("*cacheSize = %d, expected 20", *cacheSize)
+	}
+	if *dbAddr != "localhost:1234" {
+		t.Errorf("*dbAddr = %q, expected static var to be unchanged", *dbAddr)
+	}
+
+	if err := subs.Reload(testGetter{}); err == nil {
+		t.Errorf("subs.Reload() should return an error for missing var")
+	}
+
+	if err := subs.Shutdown(); err != nil {
+		t.Fatalf("subs.Shutdown() = %v, expected nil error", err)
+	}
+
+	want := []string{"db init", "cache init", "db reload", "db reload", "cache shutdown", "db shutdown"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, expected %v", calls, want)
+	}
+
+	n := 0
+	subs.Visit(func(*env.Var) { n++ })
+	if n != 2 {
+		t.Errorf("subs.Visit() visited %d vars, expected 2", n)
+	}
+}
+
+func TestSubsystemsShutdownErrors(t *testing.T) {
+	fail := func() error { return errors.New("fail") }
+	subs := env.Subsystems{
+		{Vars: env.NewVarSet("a"), Shutdown: fail},
+		{Vars: env.NewVarSet("b"), Shutdown: fail},
+	}
+
+	err := subs.Shutdown()
+	if es, ok := err.(env.Errors); !ok || len(es) != 2 {
+		t.Errorf("subs.Shutdown() = %v, expected 2 errors", err)
+	}
+}
@@ -0,0 +1,51 @@
+package env
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// typedValue is a Value storing a T, parsed and formatted with the given
+// functions.
+type typedValue[T any] struct {
+	p      *T
+	parse  func(string) (T, error)
+	format func(T) string
+}
+
+func (v *typedValue[T]) Set(x string) error {
+	t, err := v.parse(x)
+	if err != nil {
+		return err
+	}
+	*v.p = t
+	return nil
+}
+
+func (v *typedValue[T]) String() string {
+	if v.format == nil {
+		return fmt.Sprint(*v.p)
+	}
+	return v.format(*v.p)
+}
+
+func (v *typedValue[T]) typeName() string { return reflect.TypeOf(v.p).Elem().String() }
+
+// TypedValue returns a Value which stores its value in p.  Set parses values with parse,
+// and String formats them with format (or fmt.Sprint if format is nil).
+func TypedValue[T any](p *T, parse func(string) (T, error), format func(T) string) Value {
+	return &typedValue[T]{p: p, parse: parse, format: format}
+}
+
+// TypedVar defines a variable of type T in the set v with specified name and usage string.
+// Values are parsed with parse, and formatted with format (or fmt.Sprint if format is nil).
+// This allows variables of any type to be defined without implementing Value.
+//
+//	port := env.TypedVar(vs, "PORT", "port to listen on", parsePort, nil)
+//
+// The return value is the address of a T variable that stores the value of the variable.
+func TypedVar[T any](v *VarSet, name, usage string, parse func(string) (T, error), format func(T) string, opts ...Option) *T {
+	p := new(T)
+	v.Var(TypedValue(p, parse, format), name, usage, opts...)
+	return p
+}
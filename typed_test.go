@@ -0,0 +1,74 @@
+package env_test
+
+import (
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+
+	"code.sajari.com/env"
+)
+
+func TestTypedVar(t *testing.T) {
+	vs := env.NewVarSet("")
+	addr := env.TypedVar(vs, "ADDR", "typed test", netip.ParseAddr, netip.Addr.String)
+	n := env.TypedVar(vs, "UINT", "typed test", func(x string) (uint64, error) {
+		return strconv.ParseUint(x, 10, 64)
+	}, nil)
+
+	tg := testGetter{
+		"ADDR": "192.168.0.1",
+		"UINT": "18446744073709551615",
+	}
+	if err := vs.Parse(tg); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+	if *addr != netip.MustParseAddr("192.168.0.1") {
+		t.Errorf("*addr = %v, expected 192.168.0.1", *addr)
+	}
+	if *n != 1<<64-1 {
+		t.Errorf("*n = %v, expected %v", *n, uint64(1<<64-1))
+	}
+
+	vs.Visit(func(v *env.Var) {
+		if s := v.Value.String(); s != tg[v.Name] {
+			t.Errorf("v.Value.String() = %q, expected %q", s, tg[v.Name])
+		}
+	})
+
+	if err := vs.Parse(testGetter{"ADDR": "not an addr", "UINT": "-1"}); err == nil {
+		t.Errorf("expected error from Parse")
+	}
+}
+
+func TestText(t *testing.T) {
+	vs := env.NewVarSet("")
+	var addr netip.Addr
+	vs.Text("ADDR", "text test", &addr)
+	var ts time.Time
+	vs.Text("TIME", "text test", &ts)
+
+	tg := testGetter{
+		"ADDR": "2001:db8::1",
+		"TIME": "2018-03-07T10:20:30Z",
+	}
+	if err := vs.Parse(tg); err != nil {
+		t.Fatalf("unexpected error from Parse: %v", err)
+	}
+	if addr != netip.MustParseAddr("2001:db8::1") {
+		t.Errorf("addr = %v, expected 2001:db8::1", addr)
+	}
+	if want := time.Date(2018, 3, 7, 10, 20, 30, 0, time.UTC); !ts.Equal(want) {
+		t.Errorf("ts = %v, expected %v", ts, want)
+	}
+
+	vs.Visit(func(v *env.Var) {
+		if s := v.Value.String(); s != tg[v.Name] {
+			t.Errorf("v.Value.String() = %q, expected %q", s, tg[v.Name])
+		}
+	})
+
+	if err := vs.Parse(testGetter{"ADDR": "not an addr", "TIME": "yesterday"}); err == nil {
+		t.Errorf("expected error from Parse")
+	}
+}
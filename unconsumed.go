@@ -0,0 +1,45 @@
+package env
+
+import "strings"
+
+// Unconsumed returns the variables in environ (in the form "key=value", as returned
+// by os.Environ) which have the set prefix but are not defined by any variable in
+// the set or its subsets, either by name or as a fallback.  It lets plugin-style
+// components receive pass-through configuration under the prefix while the set's
+// own variables are still parsed strictly.
+//
+// If the set has no prefix then every variable in environ which is not defined by
+// the set is returned.
+func (v *VarSet) Unconsumed(environ []string) map[string]string {
+	defined := make(map[string]bool)
+	v.Visit(func(x *Var) {
+		defined[x.Name] = true
+		for _, name := range x.fallbacks {
+			defined[name] = true
+		}
+	})
+
+	prefix := ""
+	if v.prefix != "" {
+		prefix = v.prefix + "_"
+	}
+
+	m := make(map[string]string)
+	for _, kv := range environ {
+		i := strings.Index(kv, "=")
+		if i < 0 {
+			continue
+		}
+		name := kv[:i]
+		if strings.HasPrefix(name, prefix) && !defined[name] {
+			m[name] = kv[i+1:]
+		}
+	}
+	return m
+}
+
+// Unconsumed returns the variables in environ which have the prefix of the default
+// set but are not defined by it (see VarSet.Unconsumed).
+func Unconsumed(environ []string) map[string]string {
+	return CmdVar.Unconsumed(environ)
+}
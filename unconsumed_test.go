@@ -0,0 +1,40 @@
+package env_test
+
+import (
+	"reflect"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestUnconsumed(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	vs.String("NAME", "name", env.Alias("OLD_NAME"))
+	vs.Sub("db").String("HOST", "database host")
+
+	environ := []string{
+		"SVC_NAME=a",
+		"SVC_OLD_NAME=b",
+		"SVC_DB_HOST=c",
+		"SVC_PLUGIN_COLOR=red",
+		"SVC_PLUGIN_EXPR=a=b",
+		"SVC_EMPTY=",
+		"OTHER=x",
+		"malformed",
+	}
+	expected := map[string]string{
+		"SVC_PLUGIN_COLOR": "red",
+		"SVC_PLUGIN_EXPR":  "a=b",
+		"SVC_EMPTY":        "",
+	}
+	if got := vs.Unconsumed(environ); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+
+	vs = env.NewVarSet("")
+	vs.String("NAME", "name")
+	expected = map[string]string{"OTHER": "x"}
+	if got := vs.Unconsumed([]string{"NAME=a", "OTHER=x"}); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+}
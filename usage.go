@@ -0,0 +1,84 @@
+package env
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// writeErrors writes each error in err to w on a separate line.
+func writeErrors(w io.Writer, err error) {
+	es, ok := err.(Errors)
+	if !ok {
+		es = Errors{err}
+	}
+	for _, e := range es {
+		fmt.Fprintln(w, e)
+	}
+}
+
+// Usage writes the usage of each variable in the set to w, analogous to
+// flag.PrintDefaults: its name, type, whether it is required, and its usage string
+// followed by its constraint and example (see Describe) and default if known.
+//
+//	Environment variables:
+//	  SVC_WORKERS int
+//	    	number of workers (integer, i.e. "42") (default "4")
+//	  SVC_TOKEN string (required)
+//	    	api token
+//
+// Defaults of secret variables are not shown.
+func (v *VarSet) Usage(w io.Writer) {
+	fmt.Fprintf(w, "Environment variables:\n")
+	v.Visit(func(x *Var) {
+		fmt.Fprintf(w, "  %v %v", x.Name, valueType(x.Value))
+		if !x.optional && x.def == nil {
+			fmt.Fprintf(w, " (required)")
+		}
+		fmt.Fprintf(w, "\n    \t%v", x.Usage)
+		switch constraint, example := Describe(x.Value); {
+		case constraint != "" && example != "":
+			fmt.Fprintf(w, " (%v, i.e. %q)", constraint, example)
+		case constraint != "":
+			fmt.Fprintf(w, " (%v)", constraint)
+		case example != "":
+			fmt.Fprintf(w, " (i.e. %q)", example)
+		}
+		if x.def != nil && !x.secret {
+			fmt.Fprintf(w, " (default %q)", x.def())
+		}
+		fmt.Fprintln(w)
+	})
+}
+
+// Usage writes the usage of each variable in the default set to w (see
+// VarSet.Usage).
+func Usage(w io.Writer) {
+	CmdVar.Usage(w)
+}
+
+// MustParse parses variables from the environment provided by the Getter.  If
+// parsing fails then the errors, followed by the usage of every variable in the
+// set, are written to stderr and the program exits with status 1.
+//
+// When stderr is a terminal the output is laid out in aligned columns, with the
+// status of each variable and usage wrapped to the terminal width (from COLUMNS),
+// and is colored unless disabled by NO_COLOR (see Color.Enabled).
+func (v *VarSet) MustParse(g Getter, opts ...ParseOption) {
+	if err := v.Parse(g, opts...); err != nil {
+		if prettyOutput(os.Stderr) {
+			v.writeUsagePretty(os.Stderr, err, ColorAuto.Enabled(os.Stderr), terminalWidth())
+			os.Exit(1)
+		}
+		writeErrors(os.Stderr, err)
+		fmt.Fprintln(os.Stderr)
+		v.Usage(os.Stderr)
+		os.Exit(1)
+	}
+}
+
+// MustParse parses variables from the process environment, exiting with status 1
+// after writing the errors and variable usage to stderr if parsing fails.
+func MustParse(opts ...ParseOption) {
+	CmdVar.MustParse(osLookup{}, opts...)
+}
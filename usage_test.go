@@ -0,0 +1,133 @@
+package env_test
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestMustParse(t *testing.T) {
+	if os.Getenv("ENV_TEST_MUST_PARSE") == "1" {
+		vs := env.NewVarSet("svc")
+		vs.Int("WORKERS", "number of workers")
+		vs.OutputFormat("FORMAT", "output format", nil)
+		vs.MustParse(testGetter{"SVC_WORKERS": "x", "FORMAT": "json"})
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestMustParse$")
+	cmd.Env = append(os.Environ(), "ENV_TEST_MUST_PARSE=1", "CLICOLOR_FORCE=")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if e, ok := err.(*exec.ExitError); !ok || e.ExitCode() != 1 {
+		t.Fatalf("got %v, expected exit status 1", err)
+	}
+
+	for _, want := range []string{
+		`could not set env SVC_WORKERS: `,
+		"Environment variables:\n",
+		"  SVC_WORKERS int (required)\n    \tnumber of workers (integer, i.e. \"42\")\n",
+		"  SVC_FORMAT string (required)\n    \toutput format (one of ",
+	} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("stderr does not contain %q:\n%v", want, stderr.String())
+		}
+	}
+}
+
+func TestMustParsePretty(t *testing.T) {
+	if os.Getenv("ENV_TEST_MUST_PARSE") == "1" {
+		vs := env.NewVarSet("svc")
+		vs.Int("WORKERS", "number of workers")
+		vs.String("NAME", "display name")
+		vs.String("REGION", "region to run in, used for selecting the nearest database replica", env.Default("us"))
+		vs.MustParse(testGetter{"SVC_WORKERS": "x"})
+		return
+	}
+
+	tests := []struct {
+		name string
+		env  []string
+		want []string
+	}{
+		{"color", []string{"CLICOLOR_FORCE=1", "NO_COLOR=", "COLUMNS=70"}, []string{
+			"\x1b[1mErrors:\x1b[0m\n",
+			"  \x1b[31m✗\x1b[0m missing env SVC_NAME\n",
+			"  \x1b[1mSVC_WORKERS\x1b[0m  \x1b[31minvalid\x1b[0m  number of workers (integer, i.e. \"42\")\n",
+			"  \x1b[1mSVC_REGION\x1b[0m   \x1b[33mdefault\x1b[0m  region to run in, used for selecting the\n",
+		}},
+		{"no color", []string{"CLICOLOR_FORCE=1", "NO_COLOR=1", "COLUMNS=70"}, []string{
+			"Errors:\n",
+			"  SVC_NAME     missing  display name\n",
+			"  SVC_REGION   default  region to run in, used for selecting the\n" + strings.Repeat(" ", 24) + "nearest database replica\n",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestMustParsePretty$")
+			cmd.Env = append(append(os.Environ(), "ENV_TEST_MUST_PARSE=1"), tt.env...)
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
+
+			err := cmd.Run()
+			if e, ok := err.(*exec.ExitError); !ok || e.ExitCode() != 1 {
+				t.Fatalf("got %v, expected exit status 1", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(stderr.String(), want) {
+					t.Errorf("stderr does not contain %q:\n%v", want, stderr.String())
+				}
+			}
+		})
+	}
+}
+
+func TestUsage(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	vs.Int("WORKERS", "number of workers", env.Default("4"))
+	vs.String("TOKEN", "api token", env.Secret(), env.Default("dev"))
+	vs.BindAddr("ADDR", "listen address")
+	vs.Bool("DEBUG", "debug logging", env.Optional())
+
+	var buf bytes.Buffer
+	vs.Usage(&buf)
+	expected := "Environment variables:\n" +
+		"  SVC_WORKERS int\n    \tnumber of workers (integer, i.e. \"42\") (default \"4\")\n" +
+		"  SVC_TOKEN string\n    \tapi token\n" +
+		"  SVC_ADDR string (required)\n    \tlisten address (bind address (host:port), i.e. \":8080\")\n" +
+		"  SVC_DEBUG bool\n    \tdebug logging (boolean, i.e. \"true\")\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("got:\n%v\nexpected:\n%v", got, expected)
+	}
+}
+
+func TestUsageTypes(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	env.TypedVar(vs, "PORT", "port", strconv.Atoi, nil, env.Optional())
+	var ip net.IP
+	vs.Text("IP", "address", &ip, env.Optional())
+	vs.DSN("DB", "database", env.Optional())
+	vs.Location("TZ", "time zone", env.Optional())
+
+	var buf bytes.Buffer
+	vs.Usage(&buf)
+	for _, want := range []string{
+		"  SVC_PORT int\n",
+		"  SVC_IP net.IP\n",
+		"  SVC_DB dsn\n",
+		"  SVC_TZ location\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("usage does not contain %q:\n%v", want, buf.String())
+		}
+	}
+}
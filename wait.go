@@ -0,0 +1,83 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Backoff between lookups in WaitFor.
+const (
+	waitMinBackoff = 100 * time.Millisecond
+	waitMaxBackoff = 5 * time.Second
+)
+
+// WaitFor blocks until each of the named variables is set in the environment provided
+// by the Getter, for deployments where secrets are injected shortly after the process
+// starts.  Names are full variable names, including the set prefix: if none are given
+// then WaitFor waits for every variable in the set which is not Optional and has no
+// Default.
+//
+// The Getter is polled with exponential backoff, from 100ms up to 5s between attempts.
+// The maximum wait is set by the deadline of ctx: if ctx is done before the variables are
+// set then the error lists those which are still missing.  Errors from a ContextGetter
+// are treated as the variable being unavailable.  Variables of the set are looked up in
+// the same way as Parse, so they may be supplied by an Alias, Deprecated name or
+// FallbackTo.
+func (v *VarSet) WaitFor(ctx context.Context, g Getter, names ...string) error {
+	vars := make(map[string]*Var)
+	wait := len(names) == 0
+	v.Visit(func(x *Var) {
+		vars[x.Name] = x
+		if wait && !x.optional && x.def == nil {
+			names = append(names, x.Name)
+		}
+	})
+
+	cg := &contextGetter{ctx: ctx, g: g}
+	backoff := waitMinBackoff
+	for {
+		var missing []string
+		var lastErr error
+		for _, name := range names {
+			cg.err = nil
+			var ok bool
+			if x := vars[name]; x != nil {
+				_, _, ok = x.lookup(cg, nil)
+			} else {
+				_, ok = cg.Get(name)
+			}
+			if !ok {
+				missing = append(missing, name)
+				if cg.err != nil {
+					lastErr = cg.err
+				}
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			if lastErr != nil && lastErr != ctx.Err() {
+				return fmt.Errorf("env still missing after waiting: %v (%v)", strings.Join(missing, ", "), lastErr)
+			}
+			return fmt.Errorf("env still missing after waiting: %v", strings.Join(missing, ", "))
+		case <-t.C:
+		}
+
+		if backoff *= 2; backoff > waitMaxBackoff {
+			backoff = waitMaxBackoff
+		}
+	}
+}
+
+// WaitFor blocks until each of the named variables is set in the process environment
+// (see VarSet.WaitFor).
+func WaitFor(ctx context.Context, names ...string) error {
+	return CmdVar.WaitFor(ctx, osLookup{}, names...)
+}
@@ -0,0 +1,67 @@
+package env_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"code.sajari.com/env"
+)
+
+// delayedGetter is a Getter whose values are only set after a number of lookups.
+type delayedGetter struct {
+	mu      sync.Mutex
+	values  testGetter
+	lookups int
+	after   int
+}
+
+func (g *delayedGetter) Get(x string) (string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.lookups++
+	if g.lookups <= g.after {
+		return "", false
+	}
+	return g.values.Get(x)
+}
+
+func TestWaitFor(t *testing.T) {
+	vs := env.NewVarSet("")
+	vs.String("TOKEN", "wait test")
+	vs.String("NAME", "wait test", env.Default("a"))
+	vs.String("OPTIONAL", "wait test", env.Optional())
+
+	g := &delayedGetter{values: testGetter{"TOKEN": "t"}, after: 2}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := vs.WaitFor(ctx, g); err != nil {
+		t.Errorf("unexpected error from WaitFor: %v", err)
+	}
+	if g.lookups != 3 {
+		t.Errorf("got %d lookups, expected 3", g.lookups)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := vs.WaitFor(ctx, g, "TOKEN", "OTHER"); err == nil {
+		t.Errorf("expected error waiting for missing var")
+	}
+}
+
+func TestWaitForAlias(t *testing.T) {
+	vs := env.NewVarSet("svc")
+	vs.String("TOKEN", "wait test", env.Alias("API_TOKEN"))
+	vs.String("REGION", "wait test", env.FallbackTo("REGION"))
+
+	g := testGetter{"SVC_API_TOKEN": "t", "REGION": "us"}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := vs.WaitFor(ctx, g); err != nil {
+		t.Errorf("unexpected error from WaitFor: %v", err)
+	}
+	if err := vs.WaitFor(ctx, g, "SVC_TOKEN"); err != nil {
+		t.Errorf("unexpected error from WaitFor: %v", err)
+	}
+}
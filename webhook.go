@@ -0,0 +1,129 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+type webhookValue struct {
+	p            **url.URL
+	allowPrivate bool
+}
+
+func (v *webhookValue) Set(x string) error {
+	u, err := url.Parse(x)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid scheme %q, expected http or https", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return errors.New("empty host")
+	}
+	if !v.allowPrivate {
+		if err := checkPublicHost(u.Hostname()); err != nil {
+			return err
+		}
+	}
+	*v.p = u
+	return nil
+}
+
+func (v *webhookValue) String() string {
+	if *v.p == nil {
+		return ""
+	}
+	return (*v.p).String()
+}
+
+func (v *webhookValue) typeName() string { return "url" }
+
+func (v *webhookValue) Constraint() string {
+	if v.allowPrivate {
+		return "http(s) URL"
+	}
+	return "http(s) URL with a public host"
+}
+
+func (v *webhookValue) Example() string { return "https://hooks.example.com/notify" }
+
+// checkPublicHost checks that host is not localhost or a loopback, private, link-local,
+// unspecified or multicast IP address, or a numeric host which is not a dotted quad.
+//
+// Host names are not resolved, so this is only a basic guard: a public name may still
+// resolve to a private address.
+func checkPublicHost(host string) error {
+	h := strings.ToLower(strings.TrimSuffix(host, "."))
+	if h == "localhost" || strings.HasSuffix(h, ".localhost") {
+		return fmt.Errorf("host %q is not public", host)
+	}
+
+	ip := net.ParseIP(h)
+	if ip == nil {
+		// Browsers and many resolvers read a host ending in a number (i.e. 127.1,
+		// 2130706433 or 0x7f000001) as an IPv4 address, so only dotted quads are allowed.
+		labels := strings.Split(h, ".")
+		if isNumericLabel(labels[len(labels)-1]) {
+			return fmt.Errorf("host %q is not a dotted quad IP address", host)
+		}
+		return nil
+	}
+	if ip.IsLoopback() || isPrivateIP(ip) || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("address %v is not public", ip)
+	}
+	return nil
+}
+
+// isNumericLabel reports whether x is a decimal, octal or hexadecimal number.
+func isNumericLabel(x string) bool {
+	if x == "" {
+		return false
+	}
+	if strings.HasPrefix(x, "0x") {
+		return strings.Trim(x[2:], "0123456789abcdef") == ""
+	}
+	return strings.Trim(x, "0123456789") == ""
+}
+
+// privateNets are the private address ranges of RFC 1918 and RFC 4193, and the shared
+// address range of RFC 6598 (carrier-grade NAT).
+var privateNets = []*net.IPNet{
+	{IP: net.IPv4(10, 0, 0, 0), Mask: net.CIDRMask(8, 32)},
+	{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)},
+	{IP: net.IPv4(172, 16, 0, 0), Mask: net.CIDRMask(12, 32)},
+	{IP: net.IPv4(192, 168, 0, 0), Mask: net.CIDRMask(16, 32)},
+	{IP: net.IP{0xfc, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, Mask: net.CIDRMask(7, 128)},
+}
+
+// isPrivateIP reports whether ip is in one of privateNets.
+func isPrivateIP(ip net.IP) bool {
+	for _, n := range privateNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookURL defines a *url.URL variable with specified name and usage string validated as an
+// http or https URL.  Unless allowPrivate is true, URLs with a host of localhost or a loopback,
+// private, link-local, unspecified or multicast IP address are rejected, giving basic protection
+// against server-side request forgery.  Host names are not resolved when checking.
+// The return value is the address of a *url.URL variable that stores the value of the variable.
+func (v *VarSet) WebhookURL(name, usage string, allowPrivate bool, opts ...Option) **url.URL {
+	p := new(*url.URL)
+	v.Var(&webhookValue{p: p, allowPrivate: allowPrivate}, name, usage, opts...)
+	return p
+}
+
+// WebhookURL defines a *url.URL variable with specified name and usage string validated as an
+// http or https URL, with a public host unless allowPrivate is true (see VarSet.WebhookURL).
+// The return value is the address of a *url.URL variable that stores the value of the variable.
+func WebhookURL(name, usage string, allowPrivate bool, opts ...Option) **url.URL {
+	return CmdVar.WebhookURL(name, usage, allowPrivate, opts...)
+}
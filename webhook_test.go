@@ -0,0 +1,67 @@
+package env_test
+
+import (
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestWebhookURL(t *testing.T) {
+	tests := []struct {
+		in           string
+		allowPrivate bool
+		wantErr      bool
+	}{
+		// Valid
+		{"https://hooks.example.com/notify", false, false},
+		{"http://93.184.216.34:8080/hook", false, false},
+		{"https://[2606:2800:220:1:248:1893:25c8:1946]/hook", false, false},
+		{"http://localhost:8080/hook", true, false},
+		{"http://10.0.0.1/hook", true, false},
+		{"http://172.32.0.1/hook", false, false},
+		{"http://[fe00::1]/hook", false, false},
+		{"http://100.128.0.1/hook", false, false},
+		{"http://2130706433/hook", true, false},
+		{"http://api.v2.example.com/hook", false, false},
+
+		// Invalid
+		{"", false, true},
+		{"ftp://example.com/hook", false, true},
+		{"https:///hook", false, true},
+		{"http://localhost/hook", false, true},
+		{"http://api.localhost/hook", false, true},
+		{"http://127.0.0.1/hook", false, true},
+		{"http://10.1.2.3/hook", false, true},
+		{"http://192.168.0.1/hook", false, true},
+		{"http://172.16.0.1/hook", false, true},
+		{"http://172.31.255.255/hook", false, true},
+		{"http://[::ffff:10.0.0.1]/hook", false, true},
+		{"http://169.254.169.254/latest/meta-data", false, true},
+		{"http://0.0.0.0/hook", false, true},
+		{"http://[::1]/hook", false, true},
+		{"http://[fe80::1]/hook", false, true},
+		{"http://[fd00::1]/hook", false, true},
+		{"http://[fc00::1]/hook", false, true},
+		{"http://100.64.0.1/hook", false, true},
+		{"http://100.127.255.255/hook", false, true},
+		{"http://2130706433/hook", false, true},
+		{"http://0x7f000001/hook", false, true},
+		{"http://127.1/hook", false, true},
+		{"http://0177.0.0.1/hook", false, true},
+		{"http://10.0.0.0x1/hook", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			u := vs.WebhookURL("WEBHOOK", "webhook test", tt.allowPrivate)
+
+			if err := vs.Parse(testGetter{"WEBHOOK": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (*u).String() != tt.in {
+				t.Errorf(" = %v, expected %v", *u, tt.in)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// weightsValue is a map of names to percentage weights, i.e. "a=70,b=30".
+type weightsValue map[string]int
+
+func (v *weightsValue) Set(x string) error {
+	items := splitList(x)
+	if len(items) == 0 {
+		return errors.New("empty list")
+	}
+
+	m := make(map[string]int, len(items))
+	total := 0
+	for _, s := range items {
+		i := strings.Index(s, "=")
+		if i < 0 {
+			return fmt.Errorf("parsing %q: expected name=weight", s)
+		}
+		name := strings.TrimSpace(s[:i])
+		if name == "" {
+			return fmt.Errorf("parsing %q: empty name", s)
+		}
+		if _, ok := m[name]; ok {
+			return fmt.Errorf("duplicate name %q", name)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(s[i+1:]))
+		if err != nil || n < 0 {
+			return fmt.Errorf("parsing %q: invalid weight", s)
+		}
+		m[name] = n
+		total += n
+	}
+	if total != 100 {
+		return fmt.Errorf("weights sum to %d, expected 100", total)
+	}
+	*v = m
+	return nil
+}
+
+func (v *weightsValue) String() string {
+	names := make([]string, 0, len(*v))
+	for name := range *v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := make([]string, len(names))
+	for i, name := range names {
+		items[i] = name + "=" + strconv.Itoa((*v)[name])
+	}
+	return strings.Join(items, ",")
+}
+
+func (v *weightsValue) typeName() string { return "weights" }
+
+func (v *weightsValue) Constraint() string {
+	return "comma separated name=weight, weights sum to 100"
+}
+
+func (v *weightsValue) Example() string { return "backendA=70,backendB=30" }
+
+// Weights defines a map[string]int variable with specified name and usage string holding
+// a comma separated list of name=weight pairs (i.e. "backendA=70,backendB=30"), typically
+// used for splitting traffic.  Weights must be non-negative integers which sum to 100.
+// The return value is the address of a map[string]int variable that stores the value of the
+// variable.
+func (v *VarSet) Weights(name, usage string, opts ...Option) *map[string]int {
+	p := new(map[string]int)
+	v.Var((*weightsValue)(p), name, usage, opts...)
+	return p
+}
+
+// Weights defines a map[string]int variable with specified name and usage string holding
+// a comma separated list of name=weight pairs which sum to 100.
+// The return value is the address of a map[string]int variable that stores the value of the
+// variable.
+func Weights(name, usage string, opts ...Option) *map[string]int {
+	return CmdVar.Weights(name, usage, opts...)
+}
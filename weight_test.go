@@ -0,0 +1,45 @@
+package env_test
+
+import (
+	"reflect"
+	"testing"
+
+	"code.sajari.com/env"
+)
+
+func TestWeights(t *testing.T) {
+	tests := []struct {
+		in      string
+		out     map[string]int
+		wantErr bool
+	}{
+		// Valid
+		{"a=100", map[string]int{"a": 100}, false},
+		{"backendA=70,backendB=30", map[string]int{"backendA": 70, "backendB": 30}, false},
+		{"a = 50, b = 50, c = 0", map[string]int{"a": 50, "b": 50, "c": 0}, false},
+
+		// Invalid
+		{"", nil, true},
+		{"a=70,b=20", nil, true},
+		{"a=70,b=40", nil, true},
+		{"a=70,a=30", nil, true},
+		{"a", nil, true},
+		{"=100", nil, true},
+		{"a=-10,b=110", nil, true},
+		{"a=x", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			vs := env.NewVarSet("")
+			w := vs.Weights("WEIGHTS", "weights test")
+
+			if err := vs.Parse(testGetter{"WEIGHTS": tt.in}); (err != nil) != tt.wantErr {
+				t.Errorf("vs.Parse() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(*w, tt.out) {
+				t.Errorf(" = %v, expected %v", *w, tt.out)
+			}
+		})
+	}
+}